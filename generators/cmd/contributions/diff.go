@@ -0,0 +1,340 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	"io"
+	"kubevirt.io/community/pkg/contributions"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// diffCommand is the name of the subcommand that compares two report
+// outputs.
+const diffCommand = "diff"
+
+type diffOptions struct {
+	OldReportFilePath string
+	NewReportFilePath string
+	OutputFormat      string
+	OutputFilePath    string
+}
+
+func gatherDiffOptions(args []string) (*diffOptions, error) {
+	o := diffOptions{}
+	fs := flag.NewFlagSet(diffCommand, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s %s [flags] <old report output> <new report output>\n", os.Args[0], diffCommand)
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&o.OutputFormat, "output-format", string(contributions.YAMLFormat), fmt.Sprintf("format of the diff, one of %v", contributions.Formats))
+	fs.StringVar(&o.OutputFilePath, "output-file-path", "", "file path to write the diff into (leave empty to print it)")
+	err := fs.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return nil, fmt.Errorf("expected the old and the new report output, got %d arguments", fs.NArg())
+	}
+	o.OldReportFilePath, o.NewReportFilePath = fs.Arg(0), fs.Arg(1)
+	return &o, nil
+}
+
+// ReportDiff is the difference between two report outputs, where users
+// are listed by the status they have in the reports (i.e. active).
+type ReportDiff struct {
+	OldWindow contributions.ReportWindow `yaml:"oldWindow"`
+	NewWindow contributions.ReportWindow `yaml:"newWindow"`
+
+	// NewlyInactive are the users that are inactive in the new report but
+	// were not in the old report, Recovered are the users that were inactive
+	// in the old report and are active in the new report.
+	NewlyInactive []string `yaml:"newlyInactive"`
+	Recovered     []string `yaml:"recovered"`
+
+	// StatusChanges are all users whose status differs between the reports,
+	// where an empty status means that the user is not in the report.
+	StatusChanges []StatusChange `yaml:"statusChanges"`
+
+	// Deltas are the changes of the scores and activity counts of the users
+	// that have been reported in both reports.
+	Deltas []UserDelta `yaml:"deltas"`
+}
+
+type StatusChange struct {
+	User string `yaml:"user"`
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+}
+
+type UserDelta struct {
+	User           string                       `yaml:"user"`
+	Score          float64                      `yaml:"score"`
+	ActivityCounts contributions.ActivityCounts `yaml:"activityCounts,omitempty"`
+}
+
+func readReport(path string) (*Report, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", path, err)
+	}
+	report := &Report{Result: &ReportResult{}}
+	err = yaml.Unmarshal(buf, report)
+	if err != nil {
+		return nil, fmt.Errorf("in file %q: %v", path, err)
+	}
+	if report.Result == nil {
+		report.Result = &ReportResult{}
+	}
+	return report, nil
+}
+
+func userStatuses(report *Report) map[string]string {
+	statuses := make(map[string]string)
+	for _, status := range report.statuses() {
+		statuses[status.userName] = status.status
+	}
+	return statuses
+}
+
+// diffReports compares the old with the new report.
+func diffReports(oldReport, newReport *Report) *ReportDiff {
+	diff := &ReportDiff{
+		NewlyInactive: []string{},
+		Recovered:     []string{},
+		StatusChanges: []StatusChange{},
+		Deltas:        []UserDelta{},
+	}
+	if oldReport.ReportOptions != nil {
+		diff.OldWindow = oldReport.ReportOptions.Window
+	}
+	if newReport.ReportOptions != nil {
+		diff.NewWindow = newReport.ReportOptions.Window
+	}
+
+	oldStatuses, newStatuses := userStatuses(oldReport), userStatuses(newReport)
+	var userNames []string
+	for userName := range oldStatuses {
+		userNames = append(userNames, userName)
+	}
+	for userName := range newStatuses {
+		if _, exists := oldStatuses[userName]; !exists {
+			userNames = append(userNames, userName)
+		}
+	}
+	sort.Strings(userNames)
+
+	for _, userName := range userNames {
+		from, to := oldStatuses[userName], newStatuses[userName]
+		if from != to {
+			diff.StatusChanges = append(diff.StatusChanges, StatusChange{User: userName, From: from, To: to})
+			if to == "inactive" {
+				diff.NewlyInactive = append(diff.NewlyInactive, userName)
+			}
+			if from == "inactive" && to == "active" {
+				diff.Recovered = append(diff.Recovered, userName)
+			}
+		}
+
+		oldActivity, oldExists := oldReport.Activity[userName]
+		newActivity, newExists := newReport.Activity[userName]
+		if !oldExists || !newExists {
+			continue
+		}
+		delta := UserDelta{
+			User:           userName,
+			Score:          newActivity.Score - oldActivity.Score,
+			ActivityCounts: contributions.ActivityCounts{},
+		}
+		for _, activityType := range contributions.ActivityTypes {
+			if countDelta := newActivity.ActivityCounts[activityType] - oldActivity.ActivityCounts[activityType]; countDelta != 0 {
+				delta.ActivityCounts[activityType] = countDelta
+			}
+		}
+		if delta.Score != 0 || len(delta.ActivityCounts) > 0 {
+			diff.Deltas = append(diff.Deltas, delta)
+		}
+	}
+	return diff
+}
+
+// Format writes the diff in the format, where CSV contains one row per
+// user with a status change or a delta.
+func (d *ReportDiff) Format(w io.Writer, format contributions.Format) error {
+	switch format {
+	case contributions.YAMLFormat:
+		return yaml.NewEncoder(w).Encode(d)
+	case contributions.JSONFormat:
+		return contributions.WriteJSON(w, d)
+	case contributions.CSVFormat:
+		return d.formatCSV(w)
+	case contributions.MarkdownFormat:
+		return d.formatMarkdown(w)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// rows returns the status change and the delta per user, sorted by user.
+func (d *ReportDiff) rows() ([]string, map[string]StatusChange, map[string]UserDelta) {
+	var userNames []string
+	statusChanges := make(map[string]StatusChange)
+	for _, statusChange := range d.StatusChanges {
+		statusChanges[statusChange.User] = statusChange
+		userNames = append(userNames, statusChange.User)
+	}
+	deltas := make(map[string]UserDelta)
+	for _, delta := range d.Deltas {
+		deltas[delta.User] = delta
+		if _, exists := statusChanges[delta.User]; !exists {
+			userNames = append(userNames, delta.User)
+		}
+	}
+	sort.Strings(userNames)
+	return userNames, statusChanges, deltas
+}
+
+func (d *ReportDiff) formatCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	header := []string{"user", "from", "to", "score"}
+	for _, activityType := range contributions.ActivityTypes {
+		header = append(header, string(activityType))
+	}
+	err := writer.Write(header)
+	if err != nil {
+		return err
+	}
+	userNames, statusChanges, deltas := d.rows()
+	for _, userName := range userNames {
+		statusChange, exists := statusChanges[userName]
+		if !exists {
+			statusChange = StatusChange{User: userName}
+		}
+		delta := deltas[userName]
+		record := []string{userName, statusChange.From, statusChange.To, formatScore(delta.Score)}
+		for _, activityType := range contributions.ActivityTypes {
+			record = append(record, strconv.Itoa(delta.ActivityCounts[activityType]))
+		}
+		err = writer.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (d *ReportDiff) formatMarkdown(w io.Writer) error {
+	var out strings.Builder
+	out.WriteString("# Contribution report diff\n\n")
+	fmt.Fprintf(&out, "%s compared to %s\n", formatWindow(d.NewWindow), formatWindow(d.OldWindow))
+	for _, section := range []struct {
+		title     string
+		userNames []string
+	}{
+		{"newly inactive users", d.NewlyInactive},
+		{"recovered users", d.Recovered},
+	} {
+		fmt.Fprintf(&out, "\n## %s\n\n", section.title)
+		if len(section.userNames) == 0 {
+			out.WriteString("none\n")
+		}
+		for _, userName := range section.userNames {
+			fmt.Fprintf(&out, "- @%s\n", userName)
+		}
+	}
+	out.WriteString("\n## changes\n\n")
+	out.WriteString("| user | from | to | score | activity |\n")
+	out.WriteString("|---|---|---|--:|---|\n")
+	userNames, statusChanges, deltas := d.rows()
+	for _, userName := range userNames {
+		statusChange, exists := statusChanges[userName]
+		from, to := "", ""
+		if exists {
+			from, to = statusName(statusChange.From), statusName(statusChange.To)
+		}
+		delta := deltas[userName]
+		var activity []string
+		for _, activityType := range contributions.ActivityTypes {
+			if countDelta, exists := delta.ActivityCounts[activityType]; exists {
+				activity = append(activity, fmt.Sprintf("%s %+d", activityType, countDelta))
+			}
+		}
+		fmt.Fprintf(&out, "| @%s | %s | %s | %+g | %s |\n", userName, from, to, delta.Score, strings.Join(activity, ", "))
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func statusName(status string) string {
+	if status == "" {
+		return "-"
+	}
+	return status
+}
+
+func formatWindow(window contributions.ReportWindow) string {
+	if window.Since.IsZero() {
+		return "unknown window"
+	}
+	return fmt.Sprintf("%s to %s", window.Since.Format(time.DateOnly), window.Until.Add(-time.Nanosecond).Format(time.DateOnly))
+}
+
+func runDiff(args []string) {
+	o, err := gatherDiffOptions(args)
+	if err != nil {
+		log.Fatalf("error parsing arguments %v: %v", args, err)
+	}
+	format, err := contributions.ParseFormat(o.OutputFormat)
+	if err != nil {
+		log.Fatalf("invalid output format: %v", err)
+	}
+	oldReport, err := readReport(o.OldReportFilePath)
+	if err != nil {
+		log.Fatalf("failed to read old report: %v", err)
+	}
+	newReport, err := readReport(o.NewReportFilePath)
+	if err != nil {
+		log.Fatalf("failed to read new report: %v", err)
+	}
+
+	var out bytes.Buffer
+	err = diffReports(oldReport, newReport).Format(&out, format)
+	if err != nil {
+		log.Fatalf("failed to format diff: %v", err)
+	}
+	if o.OutputFilePath == "" {
+		fmt.Print(out.String())
+		return
+	}
+	err = os.WriteFile(o.OutputFilePath, out.Bytes(), 0666)
+	if err != nil {
+		log.Fatalf("failed to write diff: %v", err)
+	}
+}
@@ -0,0 +1,251 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"kubevirt.io/community/pkg/contributions"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newDiffTestReports returns an old and a new report, where alice stays
+// active, bob becomes inactive, carol recovers, dave has been added, eve
+// has been dropped and the bot is skipped in both reports.
+func newDiffTestReports() (*Report, *Report) {
+	oldReport := &Report{
+		ReportOptions: &contributionReportOptions{Window: newTestWindow("2026-01-01", "2026-06-30")},
+		Result: &ReportResult{
+			ActiveUsers:   []string{"bob", "alice"},
+			InactiveUsers: []string{"carol", "eve"},
+			SkippedUsers:  map[string][]string{"bots": {"kubevirt-bot"}},
+		},
+		Activity: map[string]UserActivity{
+			"alice": {Score: 10, ActivityCounts: contributions.ActivityCounts{contributions.CommitsActivity: 5}},
+			"bob":   {Score: 6, ActivityCounts: contributions.ActivityCounts{contributions.PullRequestsReviewedActivity: 2}},
+			"carol": {Score: 0, ActivityCounts: contributions.ActivityCounts{}},
+			"eve":   {Score: 1, ActivityCounts: contributions.ActivityCounts{contributions.IssuesCreatedActivity: 1}},
+		},
+	}
+	newReport := &Report{
+		ReportOptions: &contributionReportOptions{Window: newTestWindow("2026-04-01", "2026-09-30")},
+		Result: &ReportResult{
+			ActiveUsers:   []string{"alice", "carol", "dave"},
+			InactiveUsers: []string{"bob"},
+			SkippedUsers:  map[string][]string{"bots": {"kubevirt-bot"}},
+		},
+		Activity: map[string]UserActivity{
+			"alice": {Score: 12, ActivityCounts: contributions.ActivityCounts{contributions.CommitsActivity: 6}},
+			"bob":   {Score: 0, ActivityCounts: contributions.ActivityCounts{}},
+			"carol": {Score: 3, ActivityCounts: contributions.ActivityCounts{contributions.PullRequestsReviewedActivity: 1}},
+			"dave":  {Score: 9, ActivityCounts: contributions.ActivityCounts{contributions.PullRequestsCreatedActivity: 3}},
+		},
+	}
+	return oldReport, newReport
+}
+
+func TestDiffReports(t *testing.T) {
+	oldReport, newReport := newDiffTestReports()
+	testCases := []struct {
+		name      string
+		oldReport *Report
+		newReport *Report
+		expected  *ReportDiff
+	}{
+		{
+			name:      "status transitions and deltas",
+			oldReport: oldReport,
+			newReport: newReport,
+			expected: &ReportDiff{
+				OldWindow:     oldReport.ReportOptions.Window,
+				NewWindow:     newReport.ReportOptions.Window,
+				NewlyInactive: []string{"bob"},
+				Recovered:     []string{"carol"},
+				StatusChanges: []StatusChange{
+					{User: "bob", From: "active", To: "inactive"},
+					{User: "carol", From: "inactive", To: "active"},
+					{User: "dave", From: "", To: "active"},
+					{User: "eve", From: "inactive", To: ""},
+				},
+				Deltas: []UserDelta{
+					{User: "alice", Score: 2, ActivityCounts: contributions.ActivityCounts{contributions.CommitsActivity: 1}},
+					{User: "bob", Score: -6, ActivityCounts: contributions.ActivityCounts{contributions.PullRequestsReviewedActivity: -2}},
+					{User: "carol", Score: 3, ActivityCounts: contributions.ActivityCounts{contributions.PullRequestsReviewedActivity: 1}},
+				},
+			},
+		},
+		{
+			name:      "unchanged report",
+			oldReport: oldReport,
+			newReport: oldReport,
+			expected: &ReportDiff{
+				OldWindow:     oldReport.ReportOptions.Window,
+				NewWindow:     oldReport.ReportOptions.Window,
+				NewlyInactive: []string{},
+				Recovered:     []string{},
+				StatusChanges: []StatusChange{},
+				Deltas:        []UserDelta{},
+			},
+		},
+		{
+			name:      "user added as inactive",
+			oldReport: &Report{Result: &ReportResult{}},
+			newReport: &Report{Result: &ReportResult{InactiveUsers: []string{"frank"}}},
+			expected: &ReportDiff{
+				NewlyInactive: []string{"frank"},
+				Recovered:     []string{},
+				StatusChanges: []StatusChange{{User: "frank", From: "", To: "inactive"}},
+				Deltas:        []UserDelta{},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			diff := diffReports(testCase.oldReport, testCase.newReport)
+			if !reflect.DeepEqual(diff, testCase.expected) {
+				t.Errorf("diff: got %+v, want %+v", diff, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestReportDiffFormat(t *testing.T) {
+	oldReport, newReport := newDiffTestReports()
+	diff := diffReports(oldReport, newReport)
+
+	testCases := []struct {
+		name   string
+		format contributions.Format
+		check  func(t *testing.T, output string)
+	}{
+		{
+			name:   "csv",
+			format: contributions.CSVFormat,
+			check: func(t *testing.T, output string) {
+				records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+				if err != nil {
+					t.Fatalf("failed to parse csv: %v", err)
+				}
+				expectedHeader := []string{"user", "from", "to", "score"}
+				for _, activityType := range contributions.ActivityTypes {
+					expectedHeader = append(expectedHeader, string(activityType))
+				}
+				if !reflect.DeepEqual(records[0], expectedHeader) {
+					t.Errorf("header: got %v, want %v", records[0], expectedHeader)
+				}
+				column := func(activityType contributions.ActivityType) int {
+					for i, name := range expectedHeader {
+						if name == string(activityType) {
+							return i
+						}
+					}
+					t.Fatalf("no column for %s", activityType)
+					return 0
+				}
+				var rows [][]string
+				for _, record := range records[1:] {
+					rows = append(rows, []string{record[0], record[1], record[2], record[3], record[column(contributions.CommitsActivity)], record[column(contributions.PullRequestsReviewedActivity)]})
+				}
+				expectedRows := [][]string{
+					{"alice", "", "", "2", "1", "0"},
+					{"bob", "active", "inactive", "-6", "0", "-2"},
+					{"carol", "inactive", "active", "3", "0", "1"},
+					{"dave", "", "active", "0", "0", "0"},
+					{"eve", "inactive", "", "0", "0", "0"},
+				}
+				if !reflect.DeepEqual(rows, expectedRows) {
+					t.Errorf("rows: got %v, want %v", rows, expectedRows)
+				}
+			},
+		},
+		{
+			name:   "markdown",
+			format: contributions.MarkdownFormat,
+			check: func(t *testing.T, output string) {
+				expected := `# Contribution report diff
+
+2026-04-01 to 2026-09-30 compared to 2026-01-01 to 2026-06-30
+
+## newly inactive users
+
+- @bob
+
+## recovered users
+
+- @carol
+
+## changes
+
+| user | from | to | score | activity |
+|---|---|---|--:|---|
+| @alice |  |  | +2 | commits +1 |
+| @bob | active | inactive | -6 | pullRequestsReviewed -2 |
+| @carol | inactive | active | +3 | pullRequestsReviewed +1 |
+| @dave | - | active | +0 |  |
+| @eve | inactive | - | +0 |  |
+`
+				if output != expected {
+					t.Errorf("markdown: got\n%s\nwant\n%s", output, expected)
+				}
+			},
+		},
+		{
+			name:   "yaml",
+			format: contributions.YAMLFormat,
+			check: func(t *testing.T, output string) {
+				for _, expected := range []string{
+					"newlyInactive:\n    - bob\n",
+					"recovered:\n    - carol\n",
+					"    - user: eve\n      from: inactive\n      to: \"\"\n",
+				} {
+					if !strings.Contains(output, expected) {
+						t.Errorf("yaml: expected %q in\n%s", expected, output)
+					}
+				}
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := diff.Format(&out, testCase.format)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			testCase.check(t, out.String())
+		})
+	}
+}
+
+func TestFormatWindow(t *testing.T) {
+	if window := formatWindow(contributions.ReportWindow{}); window != "unknown window" {
+		t.Errorf("zero window: got %q", window)
+	}
+	window := contributions.ReportWindow{
+		Since: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if formatted := formatWindow(window); formatted != "2026-01-01 to 2026-01-31" {
+		t.Errorf("window: got %q, want %q", formatted, "2026-01-01 to 2026-01-31")
+	}
+}
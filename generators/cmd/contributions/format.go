@@ -0,0 +1,215 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"kubevirt.io/community/pkg/contributions"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// userStatus is the outcome of the report for a user.
+type userStatus struct {
+	userName string
+	status   string
+	note     string
+}
+
+// statuses returns the outcome for every user of the report, in the order
+// of the result buckets, sorted by user name within a bucket.
+func (r *Report) statuses() []userStatus {
+	var statuses []userStatus
+	add := func(status string, userNames []string, note func(string) string) {
+		sortedUserNames := append([]string{}, userNames...)
+		sort.Strings(sortedUserNames)
+		for _, userName := range sortedUserNames {
+			statuses = append(statuses, userStatus{userName: userName, status: status, note: note(userName)})
+		}
+	}
+	noNote := func(string) string { return "" }
+
+	result := r.Result
+	add("inactive", result.InactiveUsers, noNote)
+	add("active", result.ActiveUsers, noNote)
+	var candidates []string
+	nominatedRoles := make(map[string]contributions.Role)
+	for _, candidate := range r.PromotionCandidates {
+		candidates = append(candidates, candidate.User)
+		nominatedRoles[candidate.User] = candidate.NominatedRole
+	}
+	add("candidate", candidates, func(userName string) string { return "nominated for " + string(nominatedRoles[userName]) })
+	var reported []string
+	for userName := range r.Activity {
+		if !contains(result.ActiveUsers, userName) && !contains(result.InactiveUsers, userName) && !contains(candidates, userName) {
+			reported = append(reported, userName)
+		}
+	}
+	add("reported", reported, noNote)
+	var reasons []string
+	for reason := range result.SkippedUsers {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		add("skipped", result.SkippedUsers[reason], func(string) string { return reason })
+	}
+	var renamed []string
+	for userName := range result.RenamedUsers {
+		renamed = append(renamed, userName)
+	}
+	add("renamed", renamed, func(userName string) string { return "renamed to " + result.RenamedUsers[userName] })
+	add("missing", result.MissingUsers, noNote)
+	add("bot", result.BotUsers, noNote)
+	add("failed", result.FailedUsers, noNote)
+	add("unprocessed", result.UnprocessedUsers, noNote)
+	return statuses
+}
+
+func contains(elements []string, element string) bool {
+	for _, e := range elements {
+		if e == element {
+			return true
+		}
+	}
+	return false
+}
+
+// Format writes the report in the format, where CSV contains one row per
+// user and Markdown contains a section per outcome.
+func (r *Report) Format(w io.Writer, format contributions.Format) error {
+	switch format {
+	case contributions.YAMLFormat:
+		return yaml.NewEncoder(w).Encode(r)
+	case contributions.JSONFormat:
+		return contributions.WriteJSON(w, r)
+	case contributions.CSVFormat:
+		return r.formatCSV(w)
+	case contributions.MarkdownFormat:
+		return r.formatMarkdown(w)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+func (r *Report) formatCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	header := []string{"user", "status", "note", "role", "score", "threshold"}
+	for _, activityType := range contributions.ActivityTypes {
+		header = append(header, string(activityType))
+	}
+	err := writer.Write(header)
+	if err != nil {
+		return err
+	}
+	for _, status := range r.statuses() {
+		record := []string{status.userName, status.status, status.note}
+		activity, exists := r.Activity[status.userName]
+		if exists {
+			record = append(record, string(activity.Role), formatScore(activity.Score), formatScore(activity.Threshold))
+			for _, activityType := range contributions.ActivityTypes {
+				record = append(record, strconv.Itoa(activity.ActivityCounts[activityType]))
+			}
+		} else {
+			record = append(record, make([]string, 3+len(contributions.ActivityTypes))...)
+		}
+		err = writer.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
+
+func (r *Report) formatMarkdown(w io.Writer) error {
+	var out strings.Builder
+	out.WriteString("# Contribution report\n")
+	if r.ReportOptions != nil {
+		fmt.Fprintf(&out, "\n%s\n", formatWindow(r.ReportOptions.Window))
+	}
+	var currentStatus string
+	for _, status := range r.statuses() {
+		if status.status != currentStatus {
+			currentStatus = status.status
+			fmt.Fprintf(&out, "\n## %s users\n\n", currentStatus)
+			if currentStatus == "inactive" || currentStatus == "active" || currentStatus == "candidate" || currentStatus == "reported" {
+				out.WriteString("| user | role | score | threshold | total activity |\n")
+				out.WriteString("|---|---|--:|--:|--:|\n")
+			}
+		}
+		activity, exists := r.Activity[status.userName]
+		if !exists {
+			line := fmt.Sprintf("- @%s", status.userName)
+			if status.note != "" {
+				line += fmt.Sprintf(" (%s)", status.note)
+			}
+			out.WriteString(line + "\n")
+			continue
+		}
+		fmt.Fprintf(&out, "| @%s | %s | %s | %s | %d |\n", status.userName, activity.Role, formatScore(activity.Score), formatScore(activity.Threshold), activity.ActivityCounts.Total())
+	}
+	r.formatPromotionEvidence(&out)
+	if r.Affiliations != nil {
+		out.WriteString("\n")
+		err := r.Affiliations.Format(&out, contributions.MarkdownFormat)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// formatPromotionEvidence lists the pull requests backing the nomination of
+// each promotion candidate, ready to be linked in the sponsorship pull
+// request.
+func (r *Report) formatPromotionEvidence(out *strings.Builder) {
+	if len(r.PromotionCandidates) == 0 {
+		return
+	}
+	out.WriteString("\n## promotion evidence\n")
+	for _, candidate := range r.PromotionCandidates {
+		fmt.Fprintf(out, "\n### @%s (%s to %s)\n", candidate.User, candidate.Role, candidate.NominatedRole)
+		for _, pullRequests := range []struct {
+			name string
+			urls []string
+		}{
+			{"approved and merged", candidate.Evidence.ApprovedMerged},
+			{"reviewed", candidate.Evidence.Reviewed},
+			{"authored", candidate.Evidence.Authored},
+		} {
+			if len(pullRequests.urls) == 0 {
+				continue
+			}
+			fmt.Fprintf(out, "\n%s (%d):\n\n", pullRequests.name, len(pullRequests.urls))
+			for _, url := range pullRequests.urls {
+				fmt.Fprintf(out, "- %s\n", url)
+			}
+		}
+	}
+}
@@ -0,0 +1,221 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"kubevirt.io/community/pkg/contributions"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestWindow(since, until string) contributions.ReportWindow {
+	window, err := contributions.NewReportWindow(since, until)
+	if err != nil {
+		panic(err)
+	}
+	return window
+}
+
+func newFormatTestReport() *Report {
+	return &Report{
+		ReportOptions: &contributionReportOptions{Window: newTestWindow("2026-04-01", "2026-09-30")},
+		Result: &ReportResult{
+			ActiveUsers:   []string{"alice"},
+			InactiveUsers: []string{"bob"},
+			SkippedUsers:  map[string][]string{"bots": {"kubevirt-bot"}},
+			RenamedUsers:  map[string]string{"olduser": "newuser"},
+		},
+		Activity: map[string]UserActivity{
+			"alice": {
+				Role:      contributions.ReviewerRole,
+				Score:     12.5,
+				Threshold: 10,
+				ActivityCounts: contributions.ActivityCounts{
+					contributions.CommitsActivity:              2,
+					contributions.PullRequestsReviewedActivity: 3,
+				},
+			},
+			"bob": {
+				Role:           contributions.ApproverRole,
+				Score:          1,
+				Threshold:      20,
+				ActivityCounts: contributions.ActivityCounts{contributions.IssuesCreatedActivity: 1},
+			},
+		},
+	}
+}
+
+func TestReportFormat(t *testing.T) {
+	testCases := []struct {
+		name   string
+		format contributions.Format
+		check  func(t *testing.T, output string)
+	}{
+		{
+			name:   "csv",
+			format: contributions.CSVFormat,
+			check: func(t *testing.T, output string) {
+				records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+				if err != nil {
+					t.Fatalf("failed to parse csv: %v", err)
+				}
+				expectedHeader := []string{"user", "status", "note", "role", "score", "threshold"}
+				for _, activityType := range contributions.ActivityTypes {
+					expectedHeader = append(expectedHeader, string(activityType))
+				}
+				if !reflect.DeepEqual(records[0], expectedHeader) {
+					t.Errorf("header: got %v, want %v", records[0], expectedHeader)
+				}
+				var rows [][]string
+				for _, record := range records[1:] {
+					if len(record) != len(expectedHeader) {
+						t.Errorf("row %v: got %d columns, want %d", record, len(record), len(expectedHeader))
+						continue
+					}
+					rows = append(rows, record[:7])
+				}
+				// the first activity type column holds the created issues
+				expectedRows := [][]string{
+					{"bob", "inactive", "", "approver", "1", "20", "1"},
+					{"alice", "active", "", "reviewer", "12.5", "10", "0"},
+					{"kubevirt-bot", "skipped", "bots", "", "", "", ""},
+					{"olduser", "renamed", "renamed to newuser", "", "", "", ""},
+				}
+				if !reflect.DeepEqual(rows, expectedRows) {
+					t.Errorf("rows: got %v, want %v", rows, expectedRows)
+				}
+			},
+		},
+		{
+			name:   "markdown",
+			format: contributions.MarkdownFormat,
+			check: func(t *testing.T, output string) {
+				expected := `# Contribution report
+
+2026-04-01 to 2026-09-30
+
+## inactive users
+
+| user | role | score | threshold | total activity |
+|---|---|--:|--:|--:|
+| @bob | approver | 1 | 20 | 1 |
+
+## active users
+
+| user | role | score | threshold | total activity |
+|---|---|--:|--:|--:|
+| @alice | reviewer | 12.5 | 10 | 5 |
+
+## skipped users
+
+- @kubevirt-bot (bots)
+
+## renamed users
+
+- @olduser (renamed to newuser)
+`
+				if output != expected {
+					t.Errorf("markdown: got\n%s\nwant\n%s", output, expected)
+				}
+			},
+		},
+		{
+			name:   "json",
+			format: contributions.JSONFormat,
+			check: func(t *testing.T, output string) {
+				var report struct {
+					Result struct {
+						ActiveUsers   []string            `json:"activeUsers"`
+						InactiveUsers []string            `json:"inactiveUsers"`
+						SkippedUsers  map[string][]string `json:"skippedUsers"`
+					} `json:"result"`
+					Activity map[string]struct {
+						Score          float64        `json:"score"`
+						ActivityCounts map[string]int `json:"activityCounts"`
+					} `json:"activity"`
+				}
+				err := json.Unmarshal([]byte(output), &report)
+				if err != nil {
+					t.Fatalf("failed to parse json: %v", err)
+				}
+				if !reflect.DeepEqual(report.Result.ActiveUsers, []string{"alice"}) || !reflect.DeepEqual(report.Result.InactiveUsers, []string{"bob"}) {
+					t.Errorf("result: got active %v and inactive %v", report.Result.ActiveUsers, report.Result.InactiveUsers)
+				}
+				if !reflect.DeepEqual(report.Result.SkippedUsers, map[string][]string{"bots": {"kubevirt-bot"}}) {
+					t.Errorf("skipped users: got %v", report.Result.SkippedUsers)
+				}
+				alice := report.Activity["alice"]
+				if alice.Score != 12.5 || alice.ActivityCounts["pullRequestsReviewed"] != 3 {
+					t.Errorf("activity of alice: got %+v", alice)
+				}
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := newFormatTestReport().Format(&out, testCase.format)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			testCase.check(t, out.String())
+		})
+	}
+}
+
+func TestReportFormatPromotionEvidence(t *testing.T) {
+	report := newFormatTestReport()
+	report.PromotionCandidates = []PromotionCandidate{
+		{
+			User:          "alice",
+			Role:          contributions.ReviewerRole,
+			NominatedRole: contributions.ApproverRole,
+			Evidence: &contributions.PromotionEvidence{
+				ApprovedMerged: []string{"https://github.com/kubevirt/kubevirt/pull/1"},
+				Authored:       []string{"https://github.com/kubevirt/kubevirt/pull/2"},
+			},
+		},
+	}
+	var out bytes.Buffer
+	err := report.Format(&out, contributions.MarkdownFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `
+## promotion evidence
+
+### @alice (reviewer to approver)
+
+approved and merged (1):
+
+- https://github.com/kubevirt/kubevirt/pull/1
+
+authored (1):
+
+- https://github.com/kubevirt/kubevirt/pull/2
+`
+	if !strings.HasSuffix(out.String(), expected) {
+		t.Errorf("markdown: expected suffix\n%s\nin\n%s", expected, out.String())
+	}
+}
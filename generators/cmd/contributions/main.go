@@ -20,7 +20,10 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	_ "embed"
+	"errors"
 	"flag"
 	"fmt"
 	log "github.com/sirupsen/logrus"
@@ -28,23 +31,72 @@ import (
 	"kubevirt.io/community/pkg/contributions"
 	"kubevirt.io/community/pkg/orgs"
 	"kubevirt.io/community/pkg/owners"
+	"kubevirt.io/community/pkg/sigs"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 )
 
 type contributionReportOptions struct {
-	Org                   string `yaml:"org"`
-	Repo                  string `yaml:"repo"`
-	Username              string `yaml:"username"`
-	GithubTokenPath       string `yaml:"githubTokenPath"`
-	Months                int    `yaml:"months"`
-	OrgsConfigFilePath    string `yaml:"orgsConfigFilePath"`
-	OwnersFilePath        string `yaml:"ownersFilePath"`
-	ReportAll             bool   `yaml:"reportAll"`
-	ReportOutputFilePath  string `yaml:"reportOutputFilePath"`
-	OwnersAliasesFilePath string `yaml:"ownersAliasesFilePath"`
+	Org                   string        `yaml:"org"`
+	Repo                  string        `yaml:"repo"`
+	Scopes                string        `yaml:"scopes"`
+	Sig                   string        `yaml:"sig"`
+	SigsFilePath          string        `yaml:"sigsFilePath"`
+	Username              string        `yaml:"username"`
+	GithubTokenPath       string        `yaml:"githubTokenPath"`
+	Months                int           `yaml:"months"`
+	Since                 string        `yaml:"since"`
+	Until                 string        `yaml:"until"`
+	ReleaseCycle          string        `yaml:"releaseCycle"`
+	OrgsConfigFilePath    string        `yaml:"orgsConfigFilePath"`
+	OwnersFilePath        string        `yaml:"ownersFilePath"`
+	ReportAll             bool          `yaml:"reportAll"`
+	PromotionCandidates   bool          `yaml:"promotionCandidates"`
+	RemovalPatch          bool          `yaml:"removalPatch"`
+	RemovalPatchAliases   bool          `yaml:"removalPatchAliases"`
+	ReportOutputFilePath  string        `yaml:"reportOutputFilePath"`
+	OwnersAliasesFilePath string        `yaml:"ownersAliasesFilePath"`
+	MaxPages              int           `yaml:"maxPages"`
+	Workers               int           `yaml:"workers"`
+	RateLimitThreshold    int           `yaml:"rateLimitThreshold"`
+	CacheDir              string        `yaml:"cacheDir"`
+	FromCache             bool          `yaml:"fromCache"`
+	IncludeArchived       bool          `yaml:"includeArchived"`
+	SigChairs             bool          `yaml:"sigChairs"`
+	ScoringConfigFilePath string        `yaml:"scoringConfigFilePath"`
+	PreviousReports       string        `yaml:"previousReports"`
+	UserTimeout           time.Duration `yaml:"userTimeout"`
+	OutputFormat          string        `yaml:"outputFormat"`
+	OutputDir             string        `yaml:"outputDir"`
+	MailingListMboxPath   string        `yaml:"mailingListMboxPath"`
+	EmailMappingFilePath  string        `yaml:"emailMappingFilePath"`
+	SlackExportDir        string        `yaml:"slackExportDir"`
+	SlackChannels         string        `yaml:"slackChannels"`
+	SlackMappingFilePath  string        `yaml:"slackMappingFilePath"`
+	AffiliationsFilePath  string        `yaml:"affiliationsFilePath"`
+	IdentitiesFilePath    string        `yaml:"identitiesFilePath"`
+
+	// Window is the resolved window that the report covers
+	Window contributions.ReportWindow `yaml:"window"`
+
+	// scopes are the resolved repositories and orgs that the report covers
+	scopes []contributions.Scope
+
+	// format is the parsed output format
+	format contributions.Format
+
+	// knownUsers are the identities of users from the previous reports
+	knownUsers map[string]contributions.UserIdentity
+
+	// affiliations are the companies of the users, if the contributions
+	// are to be broken down per company
+	affiliations contributions.Affiliations
 }
 
 func (o *contributionReportOptions) defaultOwnersAliasesPath() string {
@@ -62,22 +114,261 @@ func (o *contributionReportOptions) ownersAliasesFilePath() string {
 func (o *contributionReportOptions) validate() error {
 	if o.Username != "" {
 		log.Infof("creating report for user %q", o.Username)
-	} else if o.OrgsConfigFilePath == "" && o.OwnersFilePath == "" {
-		return fmt.Errorf("username or orgs-config-file-path or owners-file-path is required")
+	} else if o.OrgsConfigFilePath == "" && o.OwnersFilePath == "" && !o.SigChairs {
+		return fmt.Errorf("username or orgs-config-file-path or owners-file-path or sig-chairs is required")
 	}
-	if o.GithubTokenPath == "" {
+	if o.GithubTokenPath == "" && !o.FromCache {
 		return fmt.Errorf("github token path is required")
 	}
+	if o.FromCache && o.CacheDir == "" {
+		return fmt.Errorf("cache dir is required when generating reports from cache")
+	}
+	if o.CacheDir != "" && o.MaxPages > 0 {
+		return fmt.Errorf("cache dir and max pages are mutually exclusive, since cached counts are derived from the fetched nodes")
+	}
+	if o.Until != "" && o.Since == "" {
+		return fmt.Errorf("since is required when until is set")
+	}
+	if o.ReleaseCycle != "" && o.Since != "" {
+		return fmt.Errorf("release cycle and since are mutually exclusive")
+	}
+	if o.ReleaseCycle != "" && o.GithubTokenPath == "" {
+		return fmt.Errorf("github token path is required to resolve the release cycle")
+	}
+	if o.OutputDir == "" {
+		return fmt.Errorf("output dir is required")
+	}
+	if o.Workers < 1 {
+		return fmt.Errorf("at least one worker is required")
+	}
+	if o.Sig != "" && o.Repo != "" {
+		return fmt.Errorf("sig and repo are mutually exclusive")
+	}
+	if o.Scopes != "" && (o.Sig != "" || o.Repo != "") {
+		return fmt.Errorf("scopes, sig and repo are mutually exclusive")
+	}
+	if o.PromotionCandidates && (o.OwnersFilePath == "" || o.Username != "" || o.SigChairs) {
+		return fmt.Errorf("promotion candidates require owners-file-path, and are mutually exclusive with username and sig-chairs")
+	}
+	if o.RemovalPatch && (o.Username != "" || o.SigChairs || o.ReportAll || o.PromotionCandidates) {
+		return fmt.Errorf("removal patch requires checking the owners or orgs file for inactive users, and is mutually exclusive with username, sig-chairs, report-all and promotion-candidates")
+	}
+	if o.RemovalPatchAliases && (!o.RemovalPatch || o.OwnersFilePath == "") {
+		return fmt.Errorf("removal patch aliases require removal-patch and owners-file-path")
+	}
+	if (o.Sig != "" || o.SigChairs) && o.SigsFilePath == "" {
+		return fmt.Errorf("sigs file path is required when sig or sig-chairs is set")
+	}
+	if o.MailingListMboxPath != "" && o.EmailMappingFilePath == "" {
+		return fmt.Errorf("email mapping file path is required when mailing list mbox path is set")
+	}
+	if o.SlackExportDir != "" && o.SlackMappingFilePath == "" {
+		return fmt.Errorf("slack mapping file path is required when slack export dir is set")
+	}
+	format, err := contributions.ParseFormat(o.OutputFormat)
+	if err != nil {
+		return fmt.Errorf("invalid output format: %v", err)
+	}
+	o.format = format
 	return nil
 }
 
-func (o *contributionReportOptions) makeGeneratorOptions() contributions.ContributionReportGeneratorOptions {
-	return contributions.ContributionReportGeneratorOptions{
-		Org:             o.Org,
-		Repo:            o.Repo,
-		GithubTokenPath: o.GithubTokenPath,
-		Months:          o.Months,
+// makeGeneratorOptions creates the options for the report generator, where
+// the window of the report is resolved from the release cycle or the since
+// and until dates, if given, and the scopes are resolved from either the
+// sig's repositories in the sigs file or the given scopes.
+func (o *contributionReportOptions) makeGeneratorOptions(ctx context.Context) (contributions.ContributionReportGeneratorOptions, error) {
+	generatorOpts := contributions.ContributionReportGeneratorOptions{
+		Org:                o.Org,
+		Repo:               o.Repo,
+		GithubTokenPath:    o.GithubTokenPath,
+		Months:             o.Months,
+		MaxPages:           o.MaxPages,
+		RateLimitThreshold: o.RateLimitThreshold,
+		CacheDir:           o.CacheDir,
+		FromCache:          o.FromCache,
+		IncludeArchived:    o.IncludeArchived,
 	}
+	switch {
+	case o.Sig != "":
+		scopes, err := o.sigScopes()
+		if err != nil {
+			return generatorOpts, err
+		}
+		generatorOpts.Scopes = scopes
+		generatorOpts.ScopesName = o.Sig
+	case o.Scopes != "":
+		scopes, err := o.parseScopes()
+		if err != nil {
+			return generatorOpts, err
+		}
+		generatorOpts.Scopes = scopes
+		generatorOpts.ScopesName = scopesNameReplacer.Replace(o.Scopes)
+	}
+	knownUsers, err := loadKnownUsers(o.PreviousReports)
+	if err != nil {
+		return generatorOpts, err
+	}
+	generatorOpts.KnownUsers = knownUsers
+	o.knownUsers = knownUsers
+	if o.MailingListMboxPath != "" {
+		generatorOpts.MailingList, err = contributions.ReadMailingListArchive(o.MailingListMboxPath)
+		if err != nil {
+			return generatorOpts, err
+		}
+		generatorOpts.EmailMapping, err = contributions.ReadEmailMapping(o.EmailMappingFilePath)
+		if err != nil {
+			return generatorOpts, err
+		}
+		log.Infof("counting %d mails of mailing list %q", len(generatorOpts.MailingList.Mails), generatorOpts.MailingList.Name)
+	}
+	if o.SlackExportDir != "" {
+		var channels []string
+		if o.SlackChannels != "" {
+			for _, channel := range strings.Split(o.SlackChannels, ",") {
+				channels = append(channels, strings.TrimSpace(channel))
+			}
+		}
+		generatorOpts.Slack, err = contributions.ReadSlackExport(o.SlackExportDir, channels)
+		if err != nil {
+			return generatorOpts, err
+		}
+		generatorOpts.SlackMapping, err = contributions.ReadSlackMapping(o.SlackMappingFilePath)
+		if err != nil {
+			return generatorOpts, err
+		}
+		log.Infof("counting %d slack messages", len(generatorOpts.Slack.Messages))
+	}
+	if o.IdentitiesFilePath != "" {
+		generatorOpts.Identities, err = contributions.ReadIdentities(o.IdentitiesFilePath)
+		if err != nil {
+			return generatorOpts, err
+		}
+	}
+	if o.AffiliationsFilePath != "" {
+		o.affiliations, err = contributions.ReadAffiliations(o.AffiliationsFilePath)
+		if err != nil {
+			return generatorOpts, err
+		}
+	}
+	o.scopes = generatorOpts.Scopes
+	if len(o.scopes) == 0 {
+		o.scopes = []contributions.Scope{{Org: o.Org, Repo: o.Repo}}
+	}
+	switch {
+	case o.ReleaseCycle != "":
+		querier, err := contributions.NewGithubQuerier(o.GithubTokenPath)
+		if err != nil {
+			return generatorOpts, err
+		}
+		window, err := contributions.ReleaseCycleWindow(ctx, querier, releaseCycleOrg, releaseCycleRepo, o.ReleaseCycle)
+		if err != nil {
+			return generatorOpts, fmt.Errorf("failed to determine release cycle %q: %v", o.ReleaseCycle, err)
+		}
+		generatorOpts.Querier = querier
+		generatorOpts.Since = window.Since
+		generatorOpts.Until = window.Until
+	case o.Until != "":
+		window, err := contributions.NewReportWindow(o.Since, o.Until)
+		if err != nil {
+			return generatorOpts, err
+		}
+		generatorOpts.Since = window.Since
+		generatorOpts.Until = window.Until
+	case o.Since != "":
+		since, err := time.Parse(time.DateOnly, o.Since)
+		if err != nil {
+			return generatorOpts, fmt.Errorf("invalid since date %q: %v", o.Since, err)
+		}
+		generatorOpts.Since = since
+	}
+	o.Window = generatorOpts.Window()
+	return generatorOpts, nil
+}
+
+// scopeName returns the name of the org, repository, sig or scopes that the
+// report covers.
+func (o *contributionReportOptions) scopeName() string {
+	switch {
+	case o.Sig != "":
+		return o.Sig
+	case o.Scopes != "":
+		return o.Scopes
+	}
+	return contributions.Scope{Org: o.Org, Repo: o.Repo}.String()
+}
+
+// loadKnownUsers reads the identities of the users from the comma separated
+// report output files of previous runs, where later files take precedence.
+func loadKnownUsers(previousReports string) (map[string]contributions.UserIdentity, error) {
+	knownUsers := make(map[string]contributions.UserIdentity)
+	if previousReports == "" {
+		return knownUsers, nil
+	}
+	for _, path := range strings.Split(previousReports, ",") {
+		path = strings.TrimSpace(path)
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %v", path, err)
+		}
+		var previousReport struct {
+			Users map[string]contributions.UserIdentity `yaml:"users"`
+		}
+		err = yaml.Unmarshal(buf, &previousReport)
+		if err != nil {
+			return nil, fmt.Errorf("in file %q: %v", path, err)
+		}
+		for login, identity := range previousReport.Users {
+			knownUsers[login] = identity
+		}
+	}
+	return knownUsers, nil
+}
+
+// scopesNameReplacer turns the scopes flag into a name that is usable in
+// file names
+var scopesNameReplacer = strings.NewReplacer("/", "_", ",", "+")
+
+// parseScopes parses the comma separated orgs and org/repo pairs.
+func (o *contributionReportOptions) parseScopes() ([]contributions.Scope, error) {
+	var scopes []contributions.Scope
+	for _, value := range strings.Split(o.Scopes, ",") {
+		scope, err := contributions.ParseScope(strings.TrimSpace(value))
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
+
+// sigScopes returns the repositories that the OWNERS files of the sig's
+// subprojects reside in.
+func (o *contributionReportOptions) sigScopes() ([]contributions.Scope, error) {
+	sigsYAML, err := sigs.ReadFile(o.SigsFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sigs file: %v", err)
+	}
+	group := sigsYAML.Group(o.Sig)
+	if group == nil {
+		return nil, fmt.Errorf("sig %q not found in %q", o.Sig, o.SigsFilePath)
+	}
+	repositories, err := group.Repositories()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine repositories of sig %q: %v", o.Sig, err)
+	}
+	if len(repositories) == 0 {
+		return nil, fmt.Errorf("sig %q has no subprojects with OWNERS files", o.Sig)
+	}
+	var scopes []contributions.Scope
+	for _, repository := range repositories {
+		scope, err := contributions.ParseScope(repository)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
 }
 
 type skipInactiveCheckConfig struct {
@@ -87,16 +378,34 @@ type skipInactiveCheckConfig struct {
 
 type contributionReportConfig struct {
 	SkipInactive map[string][]skipInactiveCheckConfig `yaml:"skipInactive"`
+
+	// SkipPromotion holds the users per org or repo that are not checked
+	// for promotion candidates, SkipInactive doesn't apply to them
+	SkipPromotion map[string][]skipInactiveCheckConfig `yaml:"skipPromotion"`
+
+	// Scoring determines the bar of activity per role that users are
+	// checked against, unset values are taken from the default scoring
+	Scoring contributions.ScoringConfig `yaml:"scoring"`
 }
 
 func (c *contributionReportConfig) ShouldSkip(org, repo, userName string) (bool, string) {
-	var skipInactiveKey string
+	return shouldSkip(c.SkipInactive, org, repo, userName)
+}
+
+// ShouldSkipPromotion checks whether the user is exempt from being listed
+// as a promotion candidate for the org or repo.
+func (c *contributionReportConfig) ShouldSkipPromotion(org, repo, userName string) (bool, string) {
+	return shouldSkip(c.SkipPromotion, org, repo, userName)
+}
+
+func shouldSkip(skipConfigs map[string][]skipInactiveCheckConfig, org, repo, userName string) (bool, string) {
+	var skipKey string
 	if repo != "" {
-		skipInactiveKey = fmt.Sprintf("%s/%s", org, repo)
+		skipKey = fmt.Sprintf("%s/%s", org, repo)
 	} else {
-		skipInactiveKey = org
+		skipKey = org
 	}
-	configs, exists := c.SkipInactive[skipInactiveKey]
+	configs, exists := skipConfigs[skipKey]
 	if !exists {
 		return false, ""
 	}
@@ -110,6 +419,13 @@ func (c *contributionReportConfig) ShouldSkip(org, repo, userName string) (bool,
 	return false, ""
 }
 
+const (
+	// releaseCycleOrg and releaseCycleRepo denote the repository whose
+	// releases define the release cycles
+	releaseCycleOrg  = "kubevirt"
+	releaseCycleRepo = "kubevirt"
+)
+
 var (
 	//go:embed default-config.yaml
 	defaultConfigContent []byte
@@ -122,14 +438,42 @@ func gatherContributionReportOptions() (*contributionReportOptions, error) {
 	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 	fs.StringVar(&o.Org, "org", "kubevirt", "org name")
 	fs.StringVar(&o.Repo, "repo", "", "repo name (leave empty to create an org activity report)")
+	fs.StringVar(&o.Scopes, "scopes", "", "comma separated orgs and org/repo pairs (i.e. kubevirt,nmstate,k8snetworkplumbingwg/kubemacpool) to create one combined report over (mutually exclusive with repo and sig)")
+	fs.StringVar(&o.Sig, "sig", "", "name or dir of the sig (i.e. sig-network) to create a report over the sig's repositories for (mutually exclusive with repo)")
+	fs.StringVar(&o.SigsFilePath, "sigs-file-path", "./sigs.yaml", "file path to the sigs.yaml file to resolve the sig's repositories from")
+	fs.BoolVar(&o.IncludeArchived, "include-archived", false, "whether to include the commits and releases in archived repositories when creating an org activity report")
 	fs.StringVar(&o.Username, "username", "", "github handle")
-	fs.IntVar(&o.Months, "months", 6, "months to look back for fetching data")
+	fs.IntVar(&o.Months, "months", 6, "months to look back for fetching data (ignored if since or release-cycle is set)")
+	fs.StringVar(&o.Since, "since", "", "start date of the report window (format: 2006-01-02)")
+	fs.StringVar(&o.Until, "until", "", "end date of the report window, inclusive (format: 2006-01-02, leave empty for today)")
+	fs.StringVar(&o.ReleaseCycle, "release-cycle", "", "KubeVirt minor release (i.e. v1.4) whose release cycle is the report window")
 	fs.StringVar(&o.GithubTokenPath, "github-token", "/etc/github/oauth", "path to github token to use")
 	fs.StringVar(&o.OrgsConfigFilePath, "orgs-file-path", "../project-infra/github/ci/prow-deploy/kustom/base/configs/current/orgs/orgs.yaml", "file path to the orgs.yaml file to check")
 	fs.StringVar(&o.OwnersFilePath, "owners-file-path", "", "file path to the OWNERS file to check")
 	fs.BoolVar(&o.ReportAll, "report-all", false, "whether to only report inactive users or all users")
+	fs.BoolVar(&o.RemovalPatch, "removal-patch", false, "whether to write a patch into the output dir that removes the inactive users from the owners file (or from the orgs file), moving approvers to the emeritus approvers")
+	fs.BoolVar(&o.RemovalPatchAliases, "removal-patch-aliases", false, "whether the removal patch also removes the inactive users from the aliases in OWNERS_ALIASES that the owners file refers to, which affects all other OWNERS files that refer to these aliases")
+	fs.BoolVar(&o.PromotionCandidates, "promotion-candidates", false, "whether to report the reviewers of the owners file (and the org members from orgs-file-path, if it exists) whose activity meets the bar of the next role, instead of inactive users")
 	fs.StringVar(&o.ReportOutputFilePath, "report-output-file-path", "", "file path to write the report output into")
 	fs.StringVar(&o.OwnersAliasesFilePath, "owners-aliases-file-path", "", "file path to resolve OWNERS file references with")
+	fs.IntVar(&o.MaxPages, "max-pages", 0, "maximum number of result pages to fetch per category (0 fetches all pages)")
+	fs.IntVar(&o.Workers, "workers", 4, "number of users to query in parallel")
+	fs.StringVar(&o.CacheDir, "cache-dir", "", "directory to cache query results in, subsequent runs then only fetch newer activity (leave empty to disable caching)")
+	fs.BoolVar(&o.FromCache, "from-cache", false, "whether to generate reports from the cache only, without querying GitHub")
+	fs.BoolVar(&o.SigChairs, "sig-chairs", false, "whether to check the chairs of all sigs (or of the sig, if set) from the sigs file")
+	fs.StringVar(&o.ScoringConfigFilePath, "scoring-config-file-path", "", "file path to a scoring configuration (weights per activity type, thresholds per role) that overrides the default scoring")
+	fs.StringVar(&o.PreviousReports, "previous-reports", "", "comma separated file paths of report outputs of previous runs, used to tell renamed from deleted accounts")
+	fs.DurationVar(&o.UserTimeout, "user-timeout", 0, "maximum time to generate the report of a single user (i.e. 10m, 0 disables the timeout)")
+	fs.StringVar(&o.OutputDir, "output-dir", os.TempDir(), "directory to write the activity logs and the index file into")
+	fs.StringVar(&o.OutputFormat, "output-format", string(contributions.YAMLFormat), fmt.Sprintf("format of the activity logs and the report output, one of %v", contributions.Formats))
+	fs.StringVar(&o.MailingListMboxPath, "mailing-list-mbox-path", "", "file path to an mbox export of the mailing list (i.e. kubevirt-dev) whose mails are counted as contributions")
+	fs.StringVar(&o.EmailMappingFilePath, "email-mapping-file-path", "", "file path to a yaml file mapping mail addresses to github handles, required with mailing-list-mbox-path")
+	fs.StringVar(&o.SlackExportDir, "slack-export-dir", "", "directory of an unpacked Slack workspace export whose messages are counted as contributions")
+	fs.StringVar(&o.SlackChannels, "slack-channels", "", "comma separated channels (i.e. virtualization,kubevirt-dev) to count the messages of (leave empty for all channels of the export)")
+	fs.StringVar(&o.SlackMappingFilePath, "slack-mapping-file-path", "", "file path to a yaml file mapping Slack user IDs to github handles, required with slack-export-dir")
+	fs.StringVar(&o.AffiliationsFilePath, "affiliations-file-path", "", "file path to a gitdm style developers_affiliations.txt file, if set the contributions of the reported users are broken down per company")
+	fs.StringVar(&o.IdentitiesFilePath, "identities-file-path", "", "file path to a yaml file mapping people to all their github handles and unlinked commit emails, whose activity is then counted towards the reported handle")
+	fs.IntVar(&o.RateLimitThreshold, "rate-limit-threshold", 100, "remaining GitHub rate limit points below which queries wait for the rate limit reset")
 	err := fs.Parse(os.Args[1:])
 	return &o, err
 }
@@ -138,13 +482,36 @@ func init() {
 	log.SetFormatter(&log.JSONFormatter{})
 	log.SetLevel(log.DebugLevel)
 
+	defaultConfig = &contributionReportConfig{
+		Scoring: contributions.DefaultScoringConfig(),
+	}
 	err := yaml.Unmarshal(defaultConfigContent, &defaultConfig)
 	if err != nil {
 		log.Fatalf("error unmarshalling default config: %v", err)
 	}
 }
 
+// loadScoringConfig overrides the scoring of the default config with the
+// values from the scoring config file, if given.
+func loadScoringConfig(scoringConfigFilePath string) error {
+	if scoringConfigFilePath != "" {
+		buf, err := os.ReadFile(scoringConfigFilePath)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %v", scoringConfigFilePath, err)
+		}
+		err = yaml.Unmarshal(buf, &defaultConfig.Scoring)
+		if err != nil {
+			return fmt.Errorf("in file %q: %v", scoringConfigFilePath, err)
+		}
+	}
+	return defaultConfig.Scoring.Validate()
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == diffCommand {
+		runDiff(os.Args[2:])
+		return
+	}
 	contributionReportOpts, err := gatherContributionReportOptions()
 	if err != nil {
 		log.Fatalf("error parsing arguments %v: %v", os.Args[1:], err)
@@ -152,13 +519,25 @@ func main() {
 	if err = contributionReportOpts.validate(); err != nil {
 		log.Fatalf("error validating arguments: %v", err)
 	}
+	if err = loadScoringConfig(contributionReportOpts.ScoringConfigFilePath); err != nil {
+		log.Fatalf("invalid scoring config: %v", err)
+	}
 
-	generator, err := contributions.NewContributionReportGenerator(contributionReportOpts.makeGeneratorOptions())
+	// on interrupt the running queries are canceled, and the report collected
+	// so far is written
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	generatorOpts, err := contributionReportOpts.makeGeneratorOptions(ctx)
+	if err != nil {
+		log.Fatalf("error determining generator options: %v", err)
+	}
+	generator, err := contributions.NewContributionReportGenerator(generatorOpts)
 	if err != nil {
 		log.Fatalf("failed to create report generator: %v", err)
 	}
 
-	newCommunityReportGenerator(contributionReportOpts, generator).generateRequestedCommunityReport()
+	newCommunityReportGenerator(contributionReportOpts, generator).generateRequestedCommunityReport(ctx)
 }
 
 func newCommunityReportGenerator(contributionReportOpts *contributionReportOptions, generator *contributions.ContributionReportGenerator) *communityReportGenerator {
@@ -174,13 +553,36 @@ type communityReportGenerator struct {
 	contributionReportGenerator *contributions.ContributionReportGenerator
 	reporter                    Reporter
 	userNames                   []string
+
+	// userRoles holds the role each user is checked in, users without
+	// an entry are checked as org members
+	userRoles map[string]contributions.Role
 }
 
-func (g *communityReportGenerator) generateRequestedCommunityReport() {
+func (g *communityReportGenerator) generateRequestedCommunityReport(ctx context.Context) {
 	g.determineReporterAndUserNames()
-	g.generateReportPerUser()
+	g.keepKnownUsers()
+	g.generateReportPerUser(ctx)
 	g.printReportSummary()
 	g.handleReportOutput()
+	if unprocessedUsers := g.reporter.Full().Result.UnprocessedUsers; len(unprocessedUsers) > 0 {
+		log.Fatalf("interrupted, the report is missing %d users: %v", len(unprocessedUsers), unprocessedUsers)
+	}
+	if failedUsers := g.reporter.Full().Result.FailedUsers; len(failedUsers) > 0 {
+		log.Fatalf("failed to generate reports for %d users: %v", len(failedUsers), failedUsers)
+	}
+}
+
+// keepKnownUsers carries the identities from the previous reports over
+// into the report, so that renames can be detected across several runs.
+func (g *communityReportGenerator) keepKnownUsers() {
+	report := g.reporter.Full()
+	for login, identity := range g.contributionReportOpts.knownUsers {
+		if report.Users == nil {
+			report.Users = make(map[string]contributions.UserIdentity)
+		}
+		report.Users[login] = identity
+	}
 }
 
 func (g *communityReportGenerator) determineReporterAndUserNames() {
@@ -190,37 +592,117 @@ func (g *communityReportGenerator) determineReporterAndUserNames() {
 		return
 	}
 
+	g.userRoles = make(map[string]contributions.Role)
+	if g.contributionReportOpts.PromotionCandidates {
+		g.determinePromotionCandidates()
+		return
+	}
 	if !g.contributionReportOpts.ReportAll {
-		g.reporter = NewInactiveOnlyReporter(g.contributionReportOpts, defaultConfig)
+		g.reporter = NewInactiveOnlyReporter(g.contributionReportOpts, defaultConfig, g.userRoles)
 	}
 
-	if g.contributionReportOpts.OwnersFilePath != "" {
-		ownersYAML, err := owners.ReadFile(g.contributionReportOpts.OwnersFilePath)
+	if g.contributionReportOpts.SigChairs {
+		sigsYAML, err := sigs.ReadFile(g.contributionReportOpts.SigsFilePath)
 		if err != nil {
 			log.Fatalf("invalid arguments: %v", err)
 		}
-		g.userNames = ownersYAML.AllReviewers()
-		g.userNames = append(g.userNames, ownersYAML.AllApprovers()...)
-
-		ownersAliasesPath := g.contributionReportOpts.ownersAliasesFilePath()
-		stat, err := os.Stat(ownersAliasesPath)
-		ownersAliases := &owners.OwnersAliases{}
-		if err == nil && !stat.IsDir() {
-			ownersAliases, err = owners.ReadAliasesFile(ownersAliasesPath)
-			if err != nil {
-				log.Fatalf("invalid aliases file %q: %v", ownersAliasesPath, err)
-			}
-		}
-		g.userNames = ownersAliases.Resolve(g.userNames)
-		g.userNames = uniq(g.userNames)
+		g.userNames = sigChairs(sigsYAML, g.contributionReportOpts.Sig)
+		g.setRole(contributions.SigChairRole, g.userNames)
+	} else if g.contributionReportOpts.OwnersFilePath != "" {
+		reviewers, approvers := g.readOwners()
+		// approvers are checked against the approver bar, even if they are
+		// listed as reviewers, too
+		g.setRole(contributions.ReviewerRole, reviewers)
+		g.setRole(contributions.ApproverRole, approvers)
+		g.userNames = uniq(reviewers, approvers)
 		sort.Strings(g.userNames)
 	} else if g.contributionReportOpts.OrgsConfigFilePath != "" {
-		orgsYAML, err := orgs.ReadFile(g.contributionReportOpts.OrgsConfigFilePath)
+		g.userNames = g.readOrgMembers()
+		g.setRole(contributions.OrgMemberRole, g.userNames)
+	}
+}
+
+// determinePromotionCandidates selects the users that can be nominated for
+// another role, which are the reviewers from the owners file and, if the
+// orgs config exists, the org members that are neither reviewers nor
+// approvers.
+func (g *communityReportGenerator) determinePromotionCandidates() {
+	g.reporter = NewPromotionCandidatesReporter(g.contributionReportOpts, defaultConfig, g.userRoles)
+	reviewers, approvers := g.readOwners()
+	if stat, err := os.Stat(g.contributionReportOpts.OrgsConfigFilePath); err == nil && !stat.IsDir() {
+		g.setRole(contributions.OrgMemberRole, g.readOrgMembers())
+	} else {
+		log.Warnf("orgs config %q not found, only checking reviewers", g.contributionReportOpts.OrgsConfigFilePath)
+	}
+	g.setRole(contributions.ReviewerRole, reviewers)
+	g.setRole(contributions.ApproverRole, approvers)
+	g.userNames = nil
+	for userName, role := range g.userRoles {
+		if _, exists := contributions.NextRole(role); exists {
+			g.userNames = append(g.userNames, userName)
+		}
+	}
+	sort.Strings(g.userNames)
+}
+
+// readOwners returns the reviewers and approvers from the owners file,
+// where aliases are resolved through the owners aliases file, if present.
+func (g *communityReportGenerator) readOwners() (reviewers []string, approvers []string) {
+	ownersYAML, err := owners.ReadFile(g.contributionReportOpts.OwnersFilePath)
+	if err != nil {
+		log.Fatalf("invalid arguments: %v", err)
+	}
+
+	ownersAliasesPath := g.contributionReportOpts.ownersAliasesFilePath()
+	stat, err := os.Stat(ownersAliasesPath)
+	ownersAliases := &owners.OwnersAliases{}
+	if err == nil && !stat.IsDir() {
+		ownersAliases, err = owners.ReadAliasesFile(ownersAliasesPath)
 		if err != nil {
-			log.Fatalf("invalid arguments: %v", err)
+			log.Fatalf("invalid aliases file %q: %v", ownersAliasesPath, err)
+		}
+	}
+	return ownersAliases.Resolve(ownersYAML.AllReviewers()), ownersAliases.Resolve(ownersYAML.AllApprovers())
+}
+
+// readOrgMembers returns the members of the org from the orgs config.
+func (g *communityReportGenerator) readOrgMembers() []string {
+	orgsYAML, err := orgs.ReadFile(g.contributionReportOpts.OrgsConfigFilePath)
+	if err != nil {
+		log.Fatalf("invalid arguments: %v", err)
+	}
+	return orgsYAML.Orgs[g.contributionReportOpts.Org].Members
+}
+
+func (g *communityReportGenerator) setRole(role contributions.Role, userNames []string) {
+	for _, userName := range userNames {
+		g.userRoles[userName] = role
+	}
+}
+
+// sigChairs returns the sorted chairs of the sig with the given name, or of
+// all sigs if name is empty.
+func sigChairs(sigsYAML *sigs.Sigs, name string) []string {
+	groups := sigsYAML.Sigs
+	if name != "" {
+		group := sigsYAML.Group(name)
+		if group == nil {
+			log.Fatalf("sig %q not found", name)
 		}
-		g.userNames = orgsYAML.Orgs[g.contributionReportOpts.Org].Members
+		groups = []*sigs.Group{group}
 	}
+	var chairs []string
+	for _, group := range groups {
+		if group.Leadership == nil {
+			continue
+		}
+		for _, chair := range group.Leadership.Chairs {
+			chairs = append(chairs, chair.Github)
+		}
+	}
+	chairs = uniq(chairs)
+	sort.Strings(chairs)
+	return chairs
 }
 
 func uniq(elements ...[]string) []string {
@@ -237,25 +719,142 @@ func uniq(elements ...[]string) []string {
 	return uniqueValues
 }
 
-func (g *communityReportGenerator) generateReportPerUser() {
+type userActivity struct {
+	userName string
+	activity contributions.ContributionReport
+	err      error
+
+	// companyActivity holds the activity counts of the user per company
+	// the user has been affiliated with during the report window
+	companyActivity []companyActivity
+}
+
+type companyActivity struct {
+	company        string
+	activityCounts contributions.ActivityCounts
+}
+
+func (g *communityReportGenerator) generateReportPerUser(ctx context.Context) {
+	var usersToQuery []*userActivity
 	for _, userName := range g.userNames {
 		if g.contributionReportOpts.Username == "" {
-			shouldSkip, reason := defaultConfig.ShouldSkip(g.contributionReportOpts.Org, g.contributionReportOpts.Repo, userName)
+			shouldSkip, reason := g.shouldSkip(userName)
 			if shouldSkip {
 				log.Debugf("skipping user %s (reason: %s)", userName, reason)
 				g.reporter.Skip(userName, reason)
 				continue
 			}
 		}
-		activity, err := g.contributionReportGenerator.GenerateReport(userName)
-		if err != nil {
-			log.Fatalf("failed to generate report: %v", err)
+		usersToQuery = append(usersToQuery, &userActivity{userName: userName})
+	}
+
+	g.queryUsers(ctx, usersToQuery)
+
+	if g.contributionReportOpts.affiliations != nil {
+		g.reporter.Full().Affiliations = contributions.NewAffiliationBreakdown(g.contributionReportOpts.scopeName(), g.contributionReportOpts.Window)
+	}
+	for _, user := range usersToQuery {
+		if ctx.Err() != nil && errors.Is(user.err, ctx.Err()) {
+			g.reporter.Full().InterruptUser(user.userName)
+			continue
+		}
+		if user.err != nil {
+			log.Errorf("failed to generate report for user %s: %v", user.userName, user.err)
+			g.reporter.Fail(user.userName, user.err)
+			continue
 		}
-		err = g.reporter.Report(activity, userName)
+		err := g.reporter.Report(user.activity, user.userName)
 		if err != nil {
 			log.Fatalf("failed to report: %v", err)
 		}
+		for _, activity := range user.companyActivity {
+			g.reporter.Full().Affiliations.Add(activity.company, user.userName, activity.activityCounts)
+		}
+	}
+}
+
+// shouldSkip checks the skip configurations of the scopes of the report,
+// the first matching configuration determines the reason. For a report over
+// several scopes, the configurations of the orgs of the scopes apply, too.
+// Promotion candidates are checked against the skipPromotion configuration,
+// since the exemptions from the inactivity check don't apply to them.
+func (g *communityReportGenerator) shouldSkip(userName string) (bool, string) {
+	configShouldSkip := defaultConfig.ShouldSkip
+	if g.contributionReportOpts.PromotionCandidates {
+		configShouldSkip = defaultConfig.ShouldSkipPromotion
+	}
+	scopes := append([]contributions.Scope{}, g.contributionReportOpts.scopes...)
+	if len(scopes) > 1 {
+		for _, scope := range g.contributionReportOpts.scopes {
+			if scope.Repo != "" {
+				scopes = append(scopes, contributions.Scope{Org: scope.Org})
+			}
+		}
+	}
+	for _, scope := range scopes {
+		shouldSkip, reason := configShouldSkip(scope.Org, scope.Repo, userName)
+		if shouldSkip {
+			return true, reason
+		}
+	}
+	return false, ""
+}
+
+// queryUsers generates the contribution reports for the given users using
+// a pool of workers, where each user is queried by exactly one worker.
+func (g *communityReportGenerator) queryUsers(ctx context.Context, users []*userActivity) {
+	usersToQuery := make(chan *userActivity)
+	var wg sync.WaitGroup
+	for i := 0; i < g.contributionReportOpts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for user := range usersToQuery {
+				g.queryUser(ctx, user)
+			}
+		}()
+	}
+	for _, user := range users {
+		if ctx.Err() != nil {
+			// users that are not queried anymore after an interrupt
+			user.err = ctx.Err()
+			continue
+		}
+		usersToQuery <- user
+	}
+	close(usersToQuery)
+	wg.Wait()
+}
+
+// queryUser generates the report of a single user, and the activity per
+// company if affiliations are set. The queries are canceled after the user
+// timeout, if set, which covers all queries of the user together.
+func (g *communityReportGenerator) queryUser(ctx context.Context, user *userActivity) {
+	if g.contributionReportOpts.UserTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.contributionReportOpts.UserTimeout)
+		defer cancel()
+	}
+	user.activity, user.err = g.contributionReportGenerator.GenerateReport(ctx, user.userName)
+	if user.err == nil && g.contributionReportOpts.affiliations != nil {
+		user.companyActivity, user.err = g.generateCompanyActivity(ctx, user.userName, user.activity)
+	}
+}
+
+func (g *communityReportGenerator) generateCompanyActivity(ctx context.Context, userName string, report contributions.ContributionReport) ([]companyActivity, error) {
+	periods := g.contributionReportOpts.affiliations.Periods(userName, g.contributionReportOpts.Window)
+	if len(periods) == 1 {
+		return []companyActivity{{company: periods[0].Company, activityCounts: report.ActivityCounts()}}, nil
+	}
+	var activity []companyActivity
+	for _, period := range periods {
+		periodReport, err := g.contributionReportGenerator.GenerateReportInWindow(ctx, userName, period.Window)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate report for affiliation with %s (%s): %w", period.Company, period.Window, err)
+		}
+		activity = append(activity, companyActivity{company: period.Company, activityCounts: periodReport.ActivityCounts()})
 	}
+	return activity, nil
 }
 
 func (g *communityReportGenerator) printReportSummary() {
@@ -267,13 +866,31 @@ func (g *communityReportGenerator) printReportSummary() {
 
 func (g *communityReportGenerator) handleReportOutput() {
 	if g.contributionReportOpts.ReportOutputFilePath != "" {
-		reportBytes, err := yaml.Marshal(g.reporter.Full())
+		var out bytes.Buffer
+		err := g.reporter.Full().Format(&out, g.contributionReportOpts.format)
 		if err != nil {
 			log.Fatalf("failed to write report: %v", err)
 		}
-		err = os.WriteFile(g.contributionReportOpts.ReportOutputFilePath, reportBytes, 0666)
+		err = os.WriteFile(g.contributionReportOpts.ReportOutputFilePath, out.Bytes(), 0666)
 		if err != nil {
 			log.Fatalf("failed to write report: %v", err)
 		}
 	}
+	if g.reporter.Full().Affiliations != nil {
+		err := g.reporter.Full().writeAffiliations()
+		if err != nil {
+			log.Fatalf("failed to write affiliations: %v", err)
+		}
+	}
+	if g.contributionReportOpts.RemovalPatch {
+		err := g.reporter.Full().writeRemovalPatch(time.Now())
+		if err != nil {
+			log.Fatalf("failed to write removal patch: %v", err)
+		}
+	}
+	indexPath, err := g.reporter.Full().writeIndex()
+	if err != nil {
+		log.Fatalf("failed to write index: %v", err)
+	}
+	log.Infof("index of the report files written to %q", indexPath)
 }
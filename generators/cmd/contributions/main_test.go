@@ -0,0 +1,94 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package main
+
+import (
+	"kubevirt.io/community/pkg/contributions"
+	"testing"
+)
+
+func TestShouldSkip(t *testing.T) {
+	config := &contributionReportConfig{
+		SkipInactive: map[string][]skipInactiveCheckConfig{
+			"kubevirt": {
+				{Name: "orgAdmins", Github: []string{"orgadmin"}},
+			},
+		},
+		SkipPromotion: map[string][]skipInactiveCheckConfig{
+			"kubevirt": {
+				{Name: "bots", Github: []string{"kubevirt-bot"}},
+			},
+		},
+	}
+	testCases := []struct {
+		name                string
+		userName            string
+		promotionCandidates bool
+		expectedSkip        bool
+		expectedReason      string
+	}{
+		{
+			name:           "inactive check skips exempt user",
+			userName:       "OrgAdmin",
+			expectedSkip:   true,
+			expectedReason: "orgAdmins",
+		},
+		{
+			name:     "inactive check ignores promotion exemptions",
+			userName: "kubevirt-bot",
+		},
+		{
+			name:                "promotion candidates ignore inactivity exemptions",
+			userName:            "orgadmin",
+			promotionCandidates: true,
+		},
+		{
+			name:                "promotion candidates skip exempt user",
+			userName:            "kubevirt-bot",
+			promotionCandidates: true,
+			expectedSkip:        true,
+			expectedReason:      "bots",
+		},
+		{
+			name:     "user without exemption",
+			userName: "testuser",
+		},
+	}
+	previousConfig := defaultConfig
+	defaultConfig = config
+	t.Cleanup(func() { defaultConfig = previousConfig })
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			g := &communityReportGenerator{
+				contributionReportOpts: &contributionReportOptions{
+					PromotionCandidates: testCase.promotionCandidates,
+					scopes:              []contributions.Scope{{Org: "kubevirt"}},
+				},
+			}
+			skip, reason := g.shouldSkip(testCase.userName)
+			if skip != testCase.expectedSkip {
+				t.Errorf("skip: got %t, want %t", skip, testCase.expectedSkip)
+			}
+			if reason != testCase.expectedReason {
+				t.Errorf("reason: got %q, want %q", reason, testCase.expectedReason)
+			}
+		})
+	}
+}
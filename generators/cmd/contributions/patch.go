@@ -0,0 +1,506 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// removalPatchFileName is the name of the file in the output dir that holds
+// the patch removing the inactive users.
+const removalPatchFileName = "removal.patch"
+
+// patchContextLines is the number of unchanged lines around each change in
+// the patch.
+const patchContextLines = 3
+
+// yamlFileEdit holds line based edits of a yaml file. The file is parsed
+// only to locate the lines to change, so that the comments, the ordering
+// and the formatting of all other lines are kept as they are.
+type yamlFileEdit struct {
+	// path is the path of the file in the patch, relative to the root of
+	// the repository the file belongs to
+	path  string
+	lines []string
+	noEOL bool
+
+	// deleted holds the indexes of the deleted lines, inserted holds the
+	// lines to insert before the line at the index, where the number of
+	// lines denotes the end of the file
+	deleted  map[int]bool
+	inserted map[int][]string
+}
+
+// readYAMLFileEdit reads the file for editing and returns it together with
+// the root node of its document.
+func readYAMLFileEdit(filePath string) (*yamlFileEdit, *yaml.Node, error) {
+	buf, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading %s: %v", filePath, err)
+	}
+	var document yaml.Node
+	err = yaml.Unmarshal(buf, &document)
+	if err != nil {
+		return nil, nil, fmt.Errorf("in file %q: %v", filePath, err)
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("in file %q: expected a mapping", filePath)
+	}
+	content := string(buf)
+	noEOL := content != "" && !strings.HasSuffix(content, "\n")
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	return &yamlFileEdit{
+		path:     patchPath(filePath),
+		lines:    lines,
+		noEOL:    noEOL,
+		deleted:  make(map[int]bool),
+		inserted: make(map[int][]string),
+	}, document.Content[0], nil
+}
+
+// patchPath returns the path of the file relative to the root of the git
+// repository it belongs to, or its base name if there is none.
+func patchPath(filePath string) string {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return filepath.Base(filePath)
+	}
+	for dir := filepath.Dir(absPath); ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			relPath, err := filepath.Rel(dir, absPath)
+			if err == nil {
+				return filepath.ToSlash(relPath)
+			}
+			break
+		}
+		if dir == filepath.Dir(dir) {
+			break
+		}
+	}
+	log.Warnf("%s is not in a git repository, using its file name in the patch", filePath)
+	return filepath.Base(filePath)
+}
+
+// mappingValue returns the value of the key in the mapping, or nil if the
+// node is not a mapping or doesn't contain the key.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// removeItems deletes the lines of the items of the sequence that are
+// contained in userNames, which holds lower case logins, and returns the
+// removed items.
+func (e *yamlFileEdit) removeItems(sequence *yaml.Node, userNames map[string]bool) ([]string, error) {
+	if sequence == nil || sequence.Kind != yaml.SequenceNode {
+		return nil, nil
+	}
+	var removed []string
+	for _, item := range sequence.Content {
+		if !userNames[strings.ToLower(item.Value)] {
+			continue
+		}
+		index := item.Line - 1
+		if sequence.Style&yaml.FlowStyle != 0 || !strings.HasPrefix(strings.TrimSpace(e.lines[index]), "-") {
+			return nil, fmt.Errorf("%s line %d: can only remove %s from block sequences", e.path, item.Line, item.Value)
+		}
+		e.deleted[index] = true
+		removed = append(removed, item.Value)
+	}
+	if len(removed) > 0 && len(removed) == len(sequence.Content) {
+		log.Warnf("%s line %d: all entries are removed, leaving the list empty", e.path, sequence.Line)
+	}
+	return removed, nil
+}
+
+// appendItems appends the items to the sequence of the key in the mapping,
+// or adds the key at the end of the file if the mapping doesn't contain
+// it yet.
+func (e *yamlFileEdit) appendItems(mapping *yaml.Node, key string, items []string) error {
+	if len(items) == 0 {
+		return nil
+	}
+	sequence := mappingValue(mapping, key)
+	switch {
+	case sequence == nil:
+		e.insert(len(e.lines), append([]string{key + ":"}, itemLines("", items)...))
+	case sequence.Kind == yaml.SequenceNode && sequence.Style&yaml.FlowStyle == 0 && len(sequence.Content) > 0:
+		lastItem := sequence.Content[len(sequence.Content)-1]
+		lastLine := e.lines[lastItem.Line-1]
+		indent := lastLine[:len(lastLine)-len(strings.TrimLeft(lastLine, " "))]
+		e.insert(lastItem.Line, itemLines(indent, items))
+	case sequence.Kind == yaml.ScalarNode && sequence.Tag == "!!null" && sequence.Value == "":
+		e.insert(sequence.Line, itemLines("", items))
+	default:
+		return fmt.Errorf("%s line %d: can only append to block sequences", e.path, sequence.Line)
+	}
+	return nil
+}
+
+func itemLines(indent string, items []string) []string {
+	var lines []string
+	for _, item := range items {
+		lines = append(lines, indent+"- "+item)
+	}
+	return lines
+}
+
+func (e *yamlFileEdit) insert(index int, lines []string) {
+	e.inserted[index] = append(e.inserted[index], lines...)
+}
+
+func (e *yamlFileEdit) changed() bool {
+	return len(e.deleted) > 0 || len(e.inserted) > 0
+}
+
+// patchLine is a line of the unified diff, where oldIndex and newIndex are
+// the indexes of the line in the old and the new file, or the index of the
+// next line if the line doesn't exist there.
+type patchLine struct {
+	kind     byte
+	text     string
+	oldIndex int
+	newIndex int
+}
+
+func (e *yamlFileEdit) patchLines() []patchLine {
+	// a line that gets followed by other lines needs a line break, which a
+	// last line without one then has to be replaced to get
+	last := len(e.lines) - 1
+	if e.noEOL && len(e.inserted[len(e.lines)]) > 0 && !e.deleted[last] {
+		e.deleted[last] = true
+		e.inserted[len(e.lines)] = append([]string{e.lines[last]}, e.inserted[len(e.lines)]...)
+	}
+	var lines []patchLine
+	newIndex := 0
+	for oldIndex := 0; oldIndex <= len(e.lines); oldIndex++ {
+		for _, text := range e.inserted[oldIndex] {
+			lines = append(lines, patchLine{kind: '+', text: text, oldIndex: oldIndex, newIndex: newIndex})
+			newIndex++
+		}
+		if oldIndex == len(e.lines) {
+			break
+		}
+		if e.deleted[oldIndex] {
+			lines = append(lines, patchLine{kind: '-', text: e.lines[oldIndex], oldIndex: oldIndex, newIndex: newIndex})
+			continue
+		}
+		lines = append(lines, patchLine{kind: ' ', text: e.lines[oldIndex], oldIndex: oldIndex, newIndex: newIndex})
+		newIndex++
+	}
+	return lines
+}
+
+// unifiedDiff returns the edits as a patch in the unified diff format, as
+// it is applied by `git apply`.
+func (e *yamlFileEdit) unifiedDiff() string {
+	lines := e.patchLines()
+	lastOld, lastNew := -1, -1
+	for i, line := range lines {
+		if line.kind != '+' {
+			lastOld = i
+		}
+		if line.kind != '-' {
+			lastNew = i
+		}
+	}
+	var out strings.Builder
+	fmt.Fprintf(&out, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n", e.path, e.path, e.path, e.path)
+	for start := 0; start < len(lines); {
+		if lines[start].kind == ' ' {
+			start++
+			continue
+		}
+		// extend the hunk as long as the next change is close enough for
+		// the context lines to overlap
+		end := start
+		for next := start + 1; next < len(lines) && next-end <= 2*patchContextLines; next++ {
+			if lines[next].kind != ' ' {
+				end = next
+			}
+		}
+		first := max(start-patchContextLines, 0)
+		last := min(end+patchContextLines, len(lines)-1)
+		oldCount, newCount := 0, 0
+		for _, line := range lines[first : last+1] {
+			if line.kind != '+' {
+				oldCount++
+			}
+			if line.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(lines[first].oldIndex, oldCount), hunkRange(lines[first].newIndex, newCount))
+		for i := first; i <= last; i++ {
+			fmt.Fprintf(&out, "%c%s\n", lines[i].kind, lines[i].text)
+			if e.noEOL && (i == lastOld && lines[i].kind != '+' || i == lastNew && lines[i].kind == '+') {
+				out.WriteString("\\ No newline at end of file\n")
+			}
+		}
+		start = last + 1
+	}
+	return out.String()
+}
+
+// hunkRange formats the start line and the number of lines of a hunk,
+// where an empty range starts at the line before it.
+func hunkRange(index, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", index)
+	}
+	return fmt.Sprintf("%d,%d", index+1, count)
+}
+
+func lowerCaseSet(userNames []string) map[string]bool {
+	set := make(map[string]bool, len(userNames))
+	for _, userName := range userNames {
+		set[strings.ToLower(userName)] = true
+	}
+	return set
+}
+
+// ownersRemovalEdits removes the users from the reviewers and approvers of
+// the OWNERS file, including its filters. If aliasesPath is set, they are
+// removed from the aliases that the OWNERS file refers to as well, which
+// also removes them from all other OWNERS files that refer to these aliases.
+// Removed approvers are added to the emeritus approvers, with the date of
+// the removal as comment.
+func ownersRemovalEdits(ownersPath, aliasesPath string, userNames []string, date time.Time) ([]*yamlFileEdit, error) {
+	inactive := lowerCaseSet(userNames)
+	ownersEdit, owners, err := readYAMLFileEdit(ownersPath)
+	if err != nil {
+		return nil, err
+	}
+	var emeritus []string
+	// referencedAliases holds the aliases the OWNERS file refers to, and
+	// whether they are referred to as approvers
+	referencedAliases := make(map[string]bool)
+	removeFrom := func(section *yaml.Node) error {
+		for _, key := range []string{"reviewers", "approvers"} {
+			sequence := mappingValue(section, key)
+			removed, err := ownersEdit.removeItems(sequence, inactive)
+			if err != nil {
+				return err
+			}
+			if key == "approvers" {
+				emeritus = append(emeritus, removed...)
+			}
+			if sequence == nil {
+				continue
+			}
+			for _, item := range sequence.Content {
+				referencedAliases[item.Value] = referencedAliases[item.Value] || key == "approvers"
+			}
+		}
+		return nil
+	}
+	err = removeFrom(owners)
+	if err != nil {
+		return nil, err
+	}
+	if filters := mappingValue(owners, "filters"); filters != nil && filters.Kind == yaml.MappingNode {
+		for i := 1; i < len(filters.Content); i += 2 {
+			err = removeFrom(filters.Content[i])
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	edits := []*yamlFileEdit{ownersEdit}
+
+	if aliasesPath != "" {
+		aliasesEdit, removedApprovers, err := aliasesRemovalEdit(aliasesPath, referencedAliases, inactive)
+		if err != nil {
+			return nil, err
+		}
+		if aliasesEdit != nil {
+			edits = append(edits, aliasesEdit)
+			emeritus = append(emeritus, removedApprovers...)
+		}
+	}
+
+	existingEmeritus := make(map[string]bool)
+	if sequence := mappingValue(owners, "emeritus_approvers"); sequence != nil {
+		for _, item := range sequence.Content {
+			existingEmeritus[strings.ToLower(item.Value)] = true
+		}
+	}
+	var emeritusItems []string
+	for _, userName := range uniq(emeritus) {
+		if !existingEmeritus[strings.ToLower(userName)] {
+			emeritusItems = append(emeritusItems, fmt.Sprintf("%s # %s", userName, date.Format("Jan 2006")))
+		}
+	}
+	sort.Strings(emeritusItems)
+	err = ownersEdit.appendItems(owners, "emeritus_approvers", emeritusItems)
+	if err != nil {
+		return nil, err
+	}
+	return edits, nil
+}
+
+// aliasesRemovalEdit removes the inactive users from the referenced aliases
+// of the OWNERS_ALIASES file, and returns the users that were removed from
+// aliases that are referred to as approvers. If the file doesn't exist, the
+// edit is nil.
+func aliasesRemovalEdit(aliasesPath string, referencedAliases, inactive map[string]bool) (*yamlFileEdit, []string, error) {
+	stat, err := os.Stat(aliasesPath)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && stat.IsDir()) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	aliasesEdit, aliasesFile, err := readYAMLFileEdit(aliasesPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	aliases := mappingValue(aliasesFile, "aliases")
+	var aliasNames []string
+	for alias := range referencedAliases {
+		aliasNames = append(aliasNames, alias)
+	}
+	sort.Strings(aliasNames)
+	var removedApprovers []string
+	for _, alias := range aliasNames {
+		removed, err := aliasesEdit.removeItems(mappingValue(aliases, alias), inactive)
+		if err != nil {
+			return nil, nil, err
+		}
+		if referencedAliases[alias] {
+			removedApprovers = append(removedApprovers, removed...)
+		}
+	}
+	return aliasesEdit, removedApprovers, nil
+}
+
+// orgsRemovalEdits removes the users from the members of the org in the
+// orgs config, and from the members and maintainers of its teams.
+func orgsRemovalEdits(orgsPath, org string, userNames []string) ([]*yamlFileEdit, error) {
+	inactive := lowerCaseSet(userNames)
+	orgsEdit, orgsFile, err := readYAMLFileEdit(orgsPath)
+	if err != nil {
+		return nil, err
+	}
+	orgConfig := mappingValue(mappingValue(orgsFile, "orgs"), org)
+	if orgConfig == nil {
+		return nil, fmt.Errorf("org %q not found in %s", org, orgsPath)
+	}
+	_, err = orgsEdit.removeItems(mappingValue(orgConfig, "members"), inactive)
+	if err != nil {
+		return nil, err
+	}
+	var removeFromTeams func(teams *yaml.Node) error
+	removeFromTeams = func(teams *yaml.Node) error {
+		if teams == nil || teams.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 1; i < len(teams.Content); i += 2 {
+			team := teams.Content[i]
+			for _, key := range []string{"members", "maintainers"} {
+				_, err := orgsEdit.removeItems(mappingValue(team, key), inactive)
+				if err != nil {
+					return err
+				}
+			}
+			err := removeFromTeams(mappingValue(team, "teams"))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	err = removeFromTeams(mappingValue(orgConfig, "teams"))
+	if err != nil {
+		return nil, err
+	}
+	return []*yamlFileEdit{orgsEdit}, nil
+}
+
+// removalPatch returns the patch that removes the inactive users from the
+// file that has been checked, or an empty patch if there is nothing to
+// remove.
+func (o *contributionReportOptions) removalPatch(inactiveUsers []string, date time.Time) (string, error) {
+	if len(inactiveUsers) == 0 {
+		return "", nil
+	}
+	var edits []*yamlFileEdit
+	var err error
+	switch {
+	case o.OwnersFilePath != "":
+		aliasesPath := ""
+		if o.RemovalPatchAliases {
+			aliasesPath = o.ownersAliasesFilePath()
+		}
+		edits, err = ownersRemovalEdits(o.OwnersFilePath, aliasesPath, inactiveUsers, date)
+	case o.OrgsConfigFilePath != "":
+		edits, err = orgsRemovalEdits(o.OrgsConfigFilePath, o.Org, inactiveUsers)
+	}
+	if err != nil {
+		return "", err
+	}
+	var patch strings.Builder
+	for _, edit := range edits {
+		if edit.changed() {
+			patch.WriteString(edit.unifiedDiff())
+		}
+	}
+	return patch.String(), nil
+}
+
+// writeRemovalPatch writes the patch that removes the inactive users into
+// the output dir.
+func (r *Report) writeRemovalPatch(date time.Time) error {
+	patch, err := r.ReportOptions.removalPatch(r.Result.InactiveUsers, date)
+	if err != nil {
+		return err
+	}
+	if patch == "" {
+		log.Infof("no inactive users to remove")
+		return nil
+	}
+	err = os.MkdirAll(r.ReportOptions.OutputDir, 0755)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(r.ReportOptions.OutputDir, removalPatchFileName)
+	err = os.WriteFile(path, []byte(patch), 0666)
+	if err != nil {
+		return err
+	}
+	log.Infof("removal patch written to %q", path)
+	r.removalPatchFile = path
+	return nil
+}
@@ -0,0 +1,143 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRemovalPatch(t *testing.T) {
+	testCases := []struct {
+		name          string
+		ownersFile    string
+		orgsFile      string
+		aliases       bool
+		inactiveUsers []string
+
+		// noEOL strips the line break at the end of the files, which the
+		// testdata files can't do without, since they are checked by the
+		// end-of-file-fixer
+		noEOL bool
+	}{
+		{
+			name:       "owners",
+			ownersFile: "OWNERS",
+			// inactiveuser is both reviewer and approver, InactiveDocs is the
+			// last item of a list, the aliases are kept as they are
+			inactiveUsers: []string{"inactiveuser", "inactivedocs", "inactivealias", "inactiveapprover"},
+		},
+		{
+			name:       "owners-aliases",
+			ownersFile: "OWNERS",
+			aliases:    true,
+			// the aliases are only removed from the referenced ones
+			inactiveUsers: []string{"inactiveuser", "inactivedocs", "inactivealias", "inactiveapprover"},
+		},
+		{
+			name:          "owners-without-emeritus",
+			ownersFile:    "OWNERS",
+			inactiveUsers: []string{"inactivereviewer", "inactiveapprover", "formerapprover"},
+			noEOL:         true,
+		},
+		{
+			name:          "owners-list-emptied",
+			ownersFile:    "OWNERS",
+			inactiveUsers: []string{"inactivereviewer", "inactiveapprover"},
+		},
+		{
+			name:          "orgs",
+			orgsFile:      "orgs.yaml",
+			inactiveUsers: []string{"inactivemember"},
+		},
+	}
+	date := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dir := copyTestdata(t, filepath.Join("testdata", "removal-patch", testCase.name), testCase.noEOL)
+			options := &contributionReportOptions{Org: "kubevirt", RemovalPatchAliases: testCase.aliases}
+			if testCase.ownersFile != "" {
+				options.OwnersFilePath = filepath.Join(dir, testCase.ownersFile)
+			}
+			if testCase.orgsFile != "" {
+				options.OrgsConfigFilePath = filepath.Join(dir, testCase.orgsFile)
+			}
+
+			patch, err := options.removalPatch(testCase.inactiveUsers, date)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected, err := os.ReadFile(filepath.Join("testdata", "removal-patch", testCase.name, "expected.patch"))
+			if err != nil {
+				t.Fatalf("failed to read expected patch: %v", err)
+			}
+			if patch != string(expected) {
+				t.Errorf("patch: got\n%s\nwant\n%s", patch, expected)
+			}
+		})
+	}
+}
+
+func TestRemovalPatchWithoutInactiveUsers(t *testing.T) {
+	options := &contributionReportOptions{OwnersFilePath: filepath.Join("testdata", "removal-patch", "owners", "OWNERS")}
+	patch, err := options.removalPatch(nil, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if patch != "" {
+		t.Errorf("patch: got %q, want empty patch", patch)
+	}
+}
+
+// copyTestdata copies the files of the testdata dir into a temporary git
+// repository, so that the paths in the patch are relative to its root. If
+// noEOL is set, the line break at the end of the files is stripped.
+func copyTestdata(t *testing.T, testdataDir string, noEOL bool) string {
+	t.Helper()
+	dir := t.TempDir()
+	err := os.Mkdir(filepath.Join(dir, ".git"), 0755)
+	if err != nil {
+		t.Fatalf("failed to create git dir: %v", err)
+	}
+	entries, err := os.ReadDir(testdataDir)
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.Name() == "expected.patch" {
+			continue
+		}
+		buf, err := os.ReadFile(filepath.Join(testdataDir, entry.Name()))
+		if err != nil {
+			t.Fatalf("failed to read testdata: %v", err)
+		}
+		if noEOL {
+			buf = bytes.TrimSuffix(buf, []byte("\n"))
+		}
+		err = os.WriteFile(filepath.Join(dir, entry.Name()), buf, 0644)
+		if err != nil {
+			t.Fatalf("failed to write testdata: %v", err)
+		}
+	}
+	return dir
+}
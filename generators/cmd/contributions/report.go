@@ -20,16 +20,34 @@
 package main
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 	"kubevirt.io/community/pkg/contributions"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 )
 
 type ReportResult struct {
 	ActiveUsers   []string            `yaml:"activeUsers"`
 	InactiveUsers []string            `yaml:"inactiveUsers"`
 	SkippedUsers  map[string][]string `yaml:"skippedUsers"`
+	FailedUsers   []string            `yaml:"failedUsers,omitempty"`
+
+	// MissingUsers are users whose login doesn't resolve to an account,
+	// RenamedUsers maps the logins of renamed accounts to their new logins
+	// and BotUsers are logins of bot accounts.
+	MissingUsers []string          `yaml:"missingUsers,omitempty"`
+	RenamedUsers map[string]string `yaml:"renamedUsers,omitempty"`
+	BotUsers     []string          `yaml:"botUsers,omitempty"`
+
+	// UnprocessedUsers are the users whose reports have not been generated
+	// because the run was interrupted.
+	UnprocessedUsers []string `yaml:"unprocessedUsers,omitempty"`
 }
 
 func (receiver *ReportResult) SkipUser(reason, userName string) {
@@ -44,6 +62,210 @@ type Report struct {
 	ReportConfig  *contributionReportConfig  `yaml:"reportConfig"`
 	Result        *ReportResult              `yaml:"result"`
 	Log           []string                   `yaml:"log"`
+
+	// ActivityLogs holds the paths of the activity logs written per user.
+	ActivityLogs map[string]string `yaml:"activityLogs,omitempty"`
+
+	// Activity holds the activity of the reported users, keyed by login.
+	Activity map[string]UserActivity `yaml:"activity,omitempty"`
+
+	// Users holds the identities of the reported users, keyed by lower case
+	// login, so that a later report can tell renamed from deleted accounts.
+	Users map[string]contributions.UserIdentity `yaml:"users,omitempty"`
+
+	// Affiliations holds the contributions of the reported users per
+	// company, if affiliations have been given.
+	Affiliations *contributions.AffiliationBreakdown `yaml:"affiliations,omitempty"`
+
+	// PromotionCandidates holds the users whose activity meets the bar of
+	// the role they can be nominated for, if promotion candidates have been
+	// requested.
+	PromotionCandidates []PromotionCandidate `yaml:"promotionCandidates,omitempty"`
+
+	// affiliationFiles holds the paths of the files the affiliations have
+	// been written to.
+	affiliationFiles []string
+
+	// removalPatchFile holds the path of the patch that removes the
+	// inactive users, if it has been written.
+	removalPatchFile string
+}
+
+// PromotionCandidate is a user that can be nominated for NominatedRole,
+// together with the pull requests to link in the sponsorship pull request.
+type PromotionCandidate struct {
+	User          string                           `yaml:"user"`
+	Role          contributions.Role               `yaml:"role"`
+	NominatedRole contributions.Role               `yaml:"nominatedRole"`
+	Evidence      *contributions.PromotionEvidence `yaml:"evidence"`
+}
+
+// FailUser records that no report could be generated for the user, the
+// cause is added to the log. Users whose login doesn't resolve to an active
+// user account are recorded in the bucket of the cause.
+func (r *Report) FailUser(userName string, err error) {
+	var renamedErr *contributions.UserRenamedError
+	switch {
+	case errors.As(err, &renamedErr):
+		if r.Result.RenamedUsers == nil {
+			r.Result.RenamedUsers = make(map[string]string)
+		}
+		r.Result.RenamedUsers[userName] = renamedErr.NewLogin
+	case errors.Is(err, contributions.ErrBotAccount):
+		r.Result.BotUsers = append(r.Result.BotUsers, userName)
+	case errors.Is(err, contributions.ErrUserNotFound), errors.Is(err, contributions.ErrUserDeleted):
+		r.Result.MissingUsers = append(r.Result.MissingUsers, userName)
+	default:
+		r.Result.FailedUsers = append(r.Result.FailedUsers, userName)
+		r.Log = append(r.Log, fmt.Sprintf("failed to generate report for user %q: %v", userName, err))
+		return
+	}
+	r.Log = append(r.Log, fmt.Sprintf("no active account for user %q: %v", userName, err))
+}
+
+// InterruptUser records that the report of the user has not been generated
+// because the run was interrupted.
+func (r *Report) InterruptUser(userName string) {
+	r.Result.UnprocessedUsers = append(r.Result.UnprocessedUsers, userName)
+}
+
+// UserActivity is the activity of a reported user, scored against the
+// threshold of the role the user is checked in.
+type UserActivity struct {
+	Role           contributions.Role           `yaml:"role,omitempty"`
+	Score          float64                      `yaml:"score"`
+	Threshold      float64                      `yaml:"threshold,omitempty"`
+	ActivityCounts contributions.ActivityCounts `yaml:"activityCounts"`
+}
+
+func (r *Report) addActivity(userName string, activity UserActivity) {
+	if r.Activity == nil {
+		r.Activity = make(map[string]UserActivity)
+	}
+	r.Activity[userName] = activity
+}
+
+// writeActivityLog writes the activity log of the user into the output
+// dir and records its path.
+func (r *Report) writeActivityLog(report contributions.ContributionReport, userName string) (string, error) {
+	path, err := report.WriteToFile(r.ReportOptions.OutputDir, userName, r.ReportOptions.format)
+	if err != nil {
+		return "", fmt.Errorf("failed to write file: %v", err)
+	}
+	if r.ActivityLogs == nil {
+		r.ActivityLogs = make(map[string]string)
+	}
+	r.ActivityLogs[userName] = path
+	return path, nil
+}
+
+// indexFileName is the name of the file in the output dir that lists all
+// files written by a run.
+const indexFileName = "index.yaml"
+
+type reportIndex struct {
+	Window       contributions.ReportWindow `yaml:"window"`
+	ReportOutput string                     `yaml:"reportOutput,omitempty"`
+	Affiliations []string                   `yaml:"affiliations,omitempty"`
+	RemovalPatch string                     `yaml:"removalPatch,omitempty"`
+	ActivityLogs []reportIndexEntry         `yaml:"activityLogs"`
+}
+
+type reportIndexEntry struct {
+	User string `yaml:"user"`
+	File string `yaml:"file"`
+}
+
+// writeIndex writes the index of the activity logs and the report output
+// into the output dir, where all files are listed by their path relative to
+// the output dir, and the activity logs are sorted by user.
+func (r *Report) writeIndex() (string, error) {
+	index := reportIndex{
+		Window:       r.ReportOptions.Window,
+		ActivityLogs: []reportIndexEntry{},
+	}
+	if r.ReportOptions.ReportOutputFilePath != "" {
+		reportOutput, err := relativeToDir(r.ReportOptions.OutputDir, r.ReportOptions.ReportOutputFilePath)
+		if err != nil {
+			return "", err
+		}
+		index.ReportOutput = reportOutput
+	}
+	if r.removalPatchFile != "" {
+		index.RemovalPatch = filepath.Base(r.removalPatchFile)
+	}
+	for _, path := range r.affiliationFiles {
+		index.Affiliations = append(index.Affiliations, filepath.Base(path))
+	}
+	for userName, path := range r.ActivityLogs {
+		index.ActivityLogs = append(index.ActivityLogs, reportIndexEntry{User: userName, File: filepath.Base(path)})
+	}
+	sort.Slice(index.ActivityLogs, func(i, j int) bool {
+		return index.ActivityLogs[i].User < index.ActivityLogs[j].User
+	})
+	buf, err := yaml.Marshal(index)
+	if err != nil {
+		return "", err
+	}
+	err = os.MkdirAll(r.ReportOptions.OutputDir, 0755)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(r.ReportOptions.OutputDir, indexFileName)
+	return path, os.WriteFile(path, buf, 0666)
+}
+
+// relativeToDir returns the path relative to the dir, where both are
+// resolved against the working directory first.
+func relativeToDir(dir, path string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(absDir, absPath)
+}
+
+// affiliationsFileName is the name of the files in the output dir that hold
+// the contributions per company, without extension.
+const affiliationsFileName = "affiliations"
+
+// writeAffiliations writes the contributions per company into the output
+// dir, both as YAML and as Markdown tables.
+func (r *Report) writeAffiliations() error {
+	err := os.MkdirAll(r.ReportOptions.OutputDir, 0755)
+	if err != nil {
+		return err
+	}
+	for _, format := range []contributions.Format{contributions.YAMLFormat, contributions.MarkdownFormat} {
+		var out bytes.Buffer
+		err = r.Affiliations.Format(&out, format)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(r.ReportOptions.OutputDir, affiliationsFileName+"."+format.Extension())
+		err = os.WriteFile(path, out.Bytes(), 0666)
+		if err != nil {
+			return err
+		}
+		log.Infof("affiliations written to %q", path)
+		r.affiliationFiles = append(r.affiliationFiles, path)
+	}
+	return nil
+}
+
+// addUser records the identity of a reported user.
+func (r *Report) addUser(identity contributions.UserIdentity) {
+	if identity.ID == "" {
+		return
+	}
+	if r.Users == nil {
+		r.Users = make(map[string]contributions.UserIdentity)
+	}
+	r.Users[strings.ToLower(identity.Login)] = identity
 }
 
 func NewReportWithConfiguration(options *contributionReportOptions, config *contributionReportConfig) *Report {
@@ -59,6 +281,7 @@ type Reporter interface {
 	Summary() string
 	Full() *Report
 	Skip(userName string, reason string)
+	Fail(userName string, err error)
 }
 
 type DefaultReporter struct {
@@ -75,13 +298,20 @@ func (d *DefaultReporter) Skip(userName string, reason string) {
 	d.report.Result.SkipUser(reason, userName)
 }
 
+func (d *DefaultReporter) Fail(userName string, err error) {
+	d.report.FailUser(userName, err)
+}
+
 func (d *DefaultReporter) Report(r contributions.ContributionReport, userName string) error {
+	d.report.addUser(r.Identity())
+	activityCounts := r.ActivityCounts()
+	d.report.addActivity(userName, UserActivity{
+		Score:          d.report.ReportConfig.Scoring.Score(activityCounts),
+		ActivityCounts: activityCounts,
+	})
 	fmt.Print(r.Summary())
-	_, err := r.WriteToFile("/tmp", userName)
-	if err != nil {
-		return fmt.Errorf("failed to write file: %v", err)
-	}
-	return nil
+	_, err := d.report.writeActivityLog(r, userName)
+	return err
 }
 
 func (d *DefaultReporter) Summary() string {
@@ -94,29 +324,61 @@ func (d *DefaultReporter) Full() *Report {
 
 type InactiveOnlyReporter struct {
 	report *Report
+	config *contributionReportConfig
+	roles  map[string]contributions.Role
 }
 
 func (d *InactiveOnlyReporter) Skip(userName string, reason string) {
 	d.report.Result.SkipUser(reason, userName)
 }
 
-func NewInactiveOnlyReporter(options *contributionReportOptions, config *contributionReportConfig) Reporter {
-	i := &InactiveOnlyReporter{}
+// NewInactiveOnlyReporter creates a reporter that checks the activity of
+// each user against the threshold of the user's role from roles, where
+// users without a role are checked as org members.
+func NewInactiveOnlyReporter(options *contributionReportOptions, config *contributionReportConfig, roles map[string]contributions.Role) Reporter {
+	i := &InactiveOnlyReporter{
+		config: config,
+		roles:  roles,
+	}
 	i.report = NewReportWithConfiguration(options, config)
 	return i
 }
 
+func (d *InactiveOnlyReporter) Fail(userName string, err error) {
+	d.report.FailUser(userName, err)
+}
+
+func (d *InactiveOnlyReporter) role(userName string) contributions.Role {
+	role, exists := d.roles[userName]
+	if !exists {
+		return contributions.OrgMemberRole
+	}
+	return role
+}
+
 func (d *InactiveOnlyReporter) Report(r contributions.ContributionReport, userName string) error {
-	if r.HasContributions() {
-		log.Debugf("active user: %s", userName)
+	d.report.addUser(r.Identity())
+	role := d.role(userName)
+	activityCounts := r.ActivityCounts()
+	score := d.config.Scoring.Score(activityCounts)
+	threshold := d.config.Scoring.Threshold(role)
+	d.report.addActivity(userName, UserActivity{
+		Role:           role,
+		Score:          score,
+		Threshold:      threshold,
+		ActivityCounts: activityCounts,
+	})
+	if score >= threshold {
+		log.Debugf("active user: %s (%s, score %.1f)", userName, role, score)
 		d.report.Result.ActiveUsers = append(d.report.Result.ActiveUsers, userName)
 		return nil
 	}
-	log.Infof("inactive user: %s", userName)
+	log.Infof("inactive user: %s (%s, score %.1f)", userName, role, score)
+	d.report.Log = append(d.report.Log, fmt.Sprintf("user %q: score %.1f below threshold %.1f for role %s", userName, score, threshold, role))
 	d.report.Log = append(d.report.Log, r.Summary())
-	fileName, err := r.WriteToFile("/tmp", userName)
+	fileName, err := d.report.writeActivityLog(r, userName)
 	if err != nil {
-		return fmt.Errorf("failed to write file: %v", err)
+		return err
 	}
 	d.report.Log = append(d.report.Log, fmt.Sprintf("activity log written to %q", fileName))
 	d.report.Result.InactiveUsers = append(d.report.Result.InactiveUsers, userName)
@@ -128,10 +390,127 @@ func (d *InactiveOnlyReporter) Summary() string {
 	if err != nil {
 		log.Fatalf("failed to serialize: %v", err)
 	}
-	return fmt.Sprintf(`inactive users:
+	summary := fmt.Sprintf(`inactive users:
 %s`, string(out))
+	return summary + d.report.Result.bucketsSummary()
+}
+
+// bucketsSummary lists the users that have not been checked, per reason.
+func (r *ReportResult) bucketsSummary() string {
+	var summary string
+	for _, bucket := range []struct {
+		name  string
+		users interface{}
+		count int
+	}{
+		{"missing users", r.MissingUsers, len(r.MissingUsers)},
+		{"renamed users", r.RenamedUsers, len(r.RenamedUsers)},
+		{"bot users", r.BotUsers, len(r.BotUsers)},
+		{"failed users", r.FailedUsers, len(r.FailedUsers)},
+		{"unprocessed users", r.UnprocessedUsers, len(r.UnprocessedUsers)},
+	} {
+		if bucket.count == 0 {
+			continue
+		}
+		out, err := yaml.Marshal(bucket.users)
+		if err != nil {
+			log.Fatalf("failed to serialize: %v", err)
+		}
+		summary += fmt.Sprintf(`%s:
+%s`, bucket.name, string(out))
+	}
+	return summary
 }
 
 func (d *InactiveOnlyReporter) Full() *Report {
 	return d.report
 }
+
+// PromotionCandidatesReporter checks the activity of each user against the
+// bar of the role that the user can be nominated for, where org members
+// can be nominated for reviewer and reviewers for approver. Users without a
+// role are checked as org members.
+type PromotionCandidatesReporter struct {
+	report *Report
+	config *contributionReportConfig
+	roles  map[string]contributions.Role
+}
+
+// NewPromotionCandidatesReporter creates a reporter that lists the users
+// whose activity meets the bar of the next role after the one from roles.
+func NewPromotionCandidatesReporter(options *contributionReportOptions, config *contributionReportConfig, roles map[string]contributions.Role) Reporter {
+	p := &PromotionCandidatesReporter{
+		config: config,
+		roles:  roles,
+	}
+	p.report = NewReportWithConfiguration(options, config)
+	return p
+}
+
+func (p *PromotionCandidatesReporter) Skip(userName string, reason string) {
+	p.report.Result.SkipUser(reason, userName)
+}
+
+func (p *PromotionCandidatesReporter) Fail(userName string, err error) {
+	p.report.FailUser(userName, err)
+}
+
+func (p *PromotionCandidatesReporter) role(userName string) contributions.Role {
+	role, exists := p.roles[userName]
+	if !exists {
+		return contributions.OrgMemberRole
+	}
+	return role
+}
+
+func (p *PromotionCandidatesReporter) Report(r contributions.ContributionReport, userName string) error {
+	p.report.addUser(r.Identity())
+	role := p.role(userName)
+	nominatedRole, exists := contributions.NextRole(role)
+	if !exists {
+		log.Debugf("user %s (%s) can not be nominated for another role", userName, role)
+		return nil
+	}
+	activityCounts := r.ActivityCounts()
+	score := p.config.Scoring.Score(activityCounts)
+	p.report.addActivity(userName, UserActivity{
+		Role:           nominatedRole,
+		Score:          score,
+		Threshold:      p.config.Scoring.Threshold(nominatedRole),
+		ActivityCounts: activityCounts,
+	})
+	evidence := contributions.NewPromotionEvidence(r)
+	meets, reasons := p.config.Scoring.MeetsPromotionBar(nominatedRole, activityCounts, evidence)
+	if !meets {
+		log.Debugf("user %s does not meet the %s bar: %s", userName, nominatedRole, strings.Join(reasons, ", "))
+		return nil
+	}
+	log.Infof("promotion candidate: %s (%s to %s, score %.1f)", userName, role, nominatedRole, score)
+	p.report.Log = append(p.report.Log, fmt.Sprintf("user %q: meets the bar for role %s", userName, nominatedRole))
+	fileName, err := p.report.writeActivityLog(r, userName)
+	if err != nil {
+		return err
+	}
+	p.report.Log = append(p.report.Log, fmt.Sprintf("activity log written to %q", fileName))
+	p.report.PromotionCandidates = append(p.report.PromotionCandidates, PromotionCandidate{
+		User:          userName,
+		Role:          role,
+		NominatedRole: nominatedRole,
+		Evidence:      evidence,
+	})
+	return nil
+}
+
+func (p *PromotionCandidatesReporter) Summary() string {
+	out, err := yaml.Marshal(p.report.PromotionCandidates)
+	if err != nil {
+		log.Fatalf("failed to serialize: %v", err)
+	}
+	summary := fmt.Sprintf(`promotion candidates:
+%s`, string(out))
+	return summary + p.report.Result.bucketsSummary()
+}
+
+func (p *PromotionCandidatesReporter) Full() *Report {
+	return p.report
+}
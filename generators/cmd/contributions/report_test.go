@@ -0,0 +1,119 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package main
+
+import (
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteIndex(t *testing.T) {
+	window := newTestWindow("2026-04-01", "2026-09-30")
+	testCases := []struct {
+		name             string
+		reportOutput     string
+		activityLogs     map[string]string
+		affiliationFiles []string
+		removalPatchFile string
+		expected         reportIndex
+	}{
+		{
+			name:         "activity logs sorted by user",
+			reportOutput: "reports/contributions-report.yaml",
+			activityLogs: map[string]string{
+				"bob":   "/reports/user-activity-bob-kubevirt-2026-04-01_2026-09-30.yaml",
+				"alice": "/reports/user-activity-alice-kubevirt-2026-04-01_2026-09-30.yaml",
+			},
+			affiliationFiles: []string{"/reports/affiliations.yaml", "/reports/affiliations.md"},
+			removalPatchFile: "/reports/removal.patch",
+			expected: reportIndex{
+				Window:       window,
+				ReportOutput: "contributions-report.yaml",
+				Affiliations: []string{"affiliations.yaml", "affiliations.md"},
+				RemovalPatch: "removal.patch",
+				ActivityLogs: []reportIndexEntry{
+					{User: "alice", File: "user-activity-alice-kubevirt-2026-04-01_2026-09-30.yaml"},
+					{User: "bob", File: "user-activity-bob-kubevirt-2026-04-01_2026-09-30.yaml"},
+				},
+			},
+		},
+		{
+			name:         "report output outside of the output dir",
+			reportOutput: "contributions-report.yaml",
+			expected: reportIndex{
+				Window:       window,
+				ReportOutput: filepath.Join("..", "contributions-report.yaml"),
+				ActivityLogs: []reportIndexEntry{},
+			},
+		},
+		{
+			name: "no files written",
+			expected: reportIndex{
+				Window:       window,
+				ActivityLogs: []reportIndexEntry{},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dir := t.TempDir()
+			outputDir := filepath.Join(dir, "reports")
+			// the report output of the test case is relative to the parent
+			// dir of the output dir
+			reportOutput := ""
+			if testCase.reportOutput != "" {
+				reportOutput = filepath.Join(dir, testCase.reportOutput)
+			}
+			report := &Report{
+				ReportOptions: &contributionReportOptions{
+					OutputDir:            outputDir,
+					ReportOutputFilePath: reportOutput,
+					Window:               window,
+				},
+				ActivityLogs:     testCase.activityLogs,
+				affiliationFiles: testCase.affiliationFiles,
+				removalPatchFile: testCase.removalPatchFile,
+			}
+
+			path, err := report.writeIndex()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if path != filepath.Join(outputDir, indexFileName) {
+				t.Errorf("path: got %q, want %q", path, filepath.Join(outputDir, indexFileName))
+			}
+			buf, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read index: %v", err)
+			}
+			var index reportIndex
+			err = yaml.Unmarshal(buf, &index)
+			if err != nil {
+				t.Fatalf("failed to parse index: %v", err)
+			}
+			if !reflect.DeepEqual(index, testCase.expected) {
+				t.Errorf("index: got %+v, want %+v", index, testCase.expected)
+			}
+		})
+	}
+}
@@ -27,8 +27,6 @@ import (
 	"kubevirt.io/community/pkg/sigs"
 	"log"
 	"os"
-	"regexp"
-	"sort"
 	"text/template"
 )
 
@@ -59,8 +57,6 @@ func gatherOptions() options {
 	return o
 }
 
-var repoNameMatcher = regexp.MustCompile(`^https://raw.githubusercontent.com/([^/]+/[^/]+)/.*$`)
-
 func main() {
 	opts := gatherOptions()
 	if err := opts.Validate(); err != nil {
@@ -72,7 +68,10 @@ func main() {
 		log.Fatalf("failed to read sigs.yaml: %v", err)
 	}
 
-	d := extractRepoGroups(sigsYAML)
+	d, err := extractRepoGroups(sigsYAML)
+	if err != nil {
+		log.Fatal(fmt.Errorf("failed to extract repo groups: %w", err))
+	}
 
 	sql, err := generateRepoGroupsSQL(d)
 	if err != nil {
@@ -97,38 +96,23 @@ func main() {
 	log.Printf("output written to %q", opts.outputPath)
 }
 
-func extractRepoGroups(sigsYAML *sigs.Sigs) RepoGroupsTemplateData {
+func extractRepoGroups(sigsYAML *sigs.Sigs) (RepoGroupsTemplateData, error) {
 	var d RepoGroupsTemplateData
 	for _, sig := range sigsYAML.Sigs {
-		repoGroup := RepoGroup{
-			Name:  sig.Name,
-			Alias: sig.Dir,
-		}
-		repoMap := make(map[string]struct{})
-		for _, subProject := range sig.SubProjects {
-			for _, ownerRef := range subProject.Owners {
-				stringSubmatch := repoNameMatcher.FindStringSubmatch(ownerRef)
-				if stringSubmatch == nil {
-					log.Fatalf("ownerRef %q doesn't match!", ownerRef)
-				}
-				repoName := stringSubmatch[1]
-				if _, exists := repoMap[repoName]; !exists {
-					repoMap[repoName] = struct{}{}
-				}
-			}
+		repos, err := sig.Repositories()
+		if err != nil {
+			return d, err
 		}
-		if len(repoMap) == 0 {
+		if len(repos) == 0 {
 			continue
 		}
-		var repos []string
-		for repo := range repoMap {
-			repos = append(repos, repo)
-		}
-		sort.Strings(repos)
-		repoGroup.Repos = repos
-		d.RepoGroups = append(d.RepoGroups, repoGroup)
+		d.RepoGroups = append(d.RepoGroups, RepoGroup{
+			Name:  sig.Name,
+			Alias: sig.Dir,
+			Repos: repos,
+		})
 	}
-	return d
+	return d, nil
 }
 
 //go:embed repo_groups.gosql
@@ -0,0 +1,142 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/shurcooL/githubv4"
+	"strings"
+)
+
+// The reasons why the login of a user doesn't resolve to an active user
+// account, besides the login never having existed (ErrUserNotFound).
+var (
+	ErrUserDeleted = errors.New("user account deleted")
+	ErrBotAccount  = errors.New("bot account")
+)
+
+// UserRenamedError reports that the account that was known under Login
+// now uses NewLogin.
+type UserRenamedError struct {
+	Login    string
+	NewLogin string
+}
+
+func (e *UserRenamedError) Error() string {
+	return fmt.Sprintf("user %s was renamed to %s", e.Login, e.NewLogin)
+}
+
+// UserIdentity identifies a GitHub account independently of its login,
+// which can be changed by the user at any time.
+type UserIdentity struct {
+	Login      string `yaml:"login"`
+	ID         string `yaml:"id"`
+	DatabaseID int64  `yaml:"databaseId"`
+}
+
+// botLoginSuffix is the suffix of the logins of GitHub App bots, i.e.
+// dependabot[bot]
+const botLoginSuffix = "[bot]"
+
+// lookupUser returns the identity of the user with the given login, where
+// the login is kept as given. If the login doesn't resolve to a user,
+// knownUsers (keyed by lower case login, usually from previous reports) is
+// used to tell whether the account was deleted or renamed. Bot accounts are
+// reported as ErrBotAccount.
+func lookupUser(ctx context.Context, client Querier, userName string, knownUsers map[string]UserIdentity) (UserIdentity, error) {
+	identity, err := queryUser(ctx, client, userName)
+	if err == nil {
+		return identity, nil
+	}
+	if !errors.Is(err, ErrUserNotFound) {
+		return UserIdentity{}, err
+	}
+	if strings.HasSuffix(strings.ToLower(userName), botLoginSuffix) {
+		return UserIdentity{}, fmt.Errorf("%w: %s", ErrBotAccount, userName)
+	}
+	known, exists := knownUsers[strings.ToLower(userName)]
+	if !exists || known.ID == "" {
+		return UserIdentity{}, err
+	}
+	return queryKnownUser(ctx, client, userName, known)
+}
+
+func queryUser(ctx context.Context, client Querier, userName string) (UserIdentity, error) {
+	var query struct {
+		User struct {
+			ID         string
+			DatabaseID int64 `graphql:"databaseId"`
+		} `graphql:"user(login: $username)"`
+	}
+	variables := map[string]interface{}{
+		"username": githubv4.String(userName),
+	}
+	err := client.Query(ctx, &query, variables)
+	if err != nil {
+		return UserIdentity{}, fmt.Errorf("failed to use github query %+v with variables %v: %w", query, variables, err)
+	}
+	if query.User.ID == "" {
+		return UserIdentity{}, &QueryError{Class: ErrUserNotFound, Err: fmt.Errorf("no user with login %q", userName)}
+	}
+	return UserIdentity{
+		Login:      userName,
+		ID:         query.User.ID,
+		DatabaseID: query.User.DatabaseID,
+	}, nil
+}
+
+// queryKnownUser looks up the account that was known under the login by
+// its node id. Like the database id, the node id is assigned once per
+// account and stays the same when the account is renamed, but unlike the
+// database id it can be resolved with the GraphQL API, which has no lookup
+// of users by database id. Thus resolving the node id tells renamed and
+// deleted accounts apart just as the database id history would.
+func queryKnownUser(ctx context.Context, client Querier, userName string, known UserIdentity) (UserIdentity, error) {
+	var query struct {
+		Node struct {
+			Typename string `graphql:"__typename"`
+			User     struct {
+				Login      string
+				DatabaseID int64 `graphql:"databaseId"`
+			} `graphql:"... on User"`
+		} `graphql:"node(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id": githubv4.ID(known.ID),
+	}
+	err := client.Query(ctx, &query, variables)
+	if err != nil && !strings.Contains(err.Error(), "Could not resolve to a node") {
+		return UserIdentity{}, fmt.Errorf("failed to use github query %+v with variables %v: %w", query, variables, err)
+	}
+	switch query.Node.Typename {
+	case "":
+		return UserIdentity{}, fmt.Errorf("%w: %s (id %s, database id %d)", ErrUserDeleted, userName, known.ID, known.DatabaseID)
+	case "Bot":
+		return UserIdentity{}, fmt.Errorf("%w: %s", ErrBotAccount, userName)
+	case "User":
+		if strings.EqualFold(query.Node.User.Login, userName) {
+			return UserIdentity{Login: userName, ID: known.ID, DatabaseID: query.Node.User.DatabaseID}, nil
+		}
+		return UserIdentity{}, &UserRenamedError{Login: userName, NewLogin: query.Node.User.Login}
+	}
+	return UserIdentity{}, fmt.Errorf("user %s: unexpected account type %s", userName, query.Node.Typename)
+}
@@ -0,0 +1,98 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLookupUser(t *testing.T) {
+	knownUsers := map[string]UserIdentity{
+		"renamed-user": {Login: "renamed-user", ID: "U_renamed", DatabaseID: 1002},
+		"deleted-user": {Login: "deleted-user", ID: "U_deleted", DatabaseID: 1003},
+		"ci-robot":     {Login: "ci-robot", ID: "BOT_robot", DatabaseID: 1004},
+	}
+	testCases := []struct {
+		name             string
+		userName         string
+		expectedIdentity UserIdentity
+		expectedErr      error
+		expectedNewLogin string
+	}{
+		{
+			name:             "existing user",
+			userName:         "testuser",
+			expectedIdentity: UserIdentity{Login: "testuser", ID: "U_testuser", DatabaseID: 1001},
+		},
+		{
+			name:             "renamed user",
+			userName:         "renamed-user",
+			expectedNewLogin: "new-name",
+		},
+		{
+			name:        "deleted user",
+			userName:    "deleted-user",
+			expectedErr: ErrUserDeleted,
+		},
+		{
+			name:        "unknown user",
+			userName:    "unknown-user",
+			expectedErr: ErrUserNotFound,
+		},
+		{
+			name:        "app bot",
+			userName:    "dependabot[bot]",
+			expectedErr: ErrBotAccount,
+		},
+		{
+			name:        "known bot",
+			userName:    "ci-robot",
+			expectedErr: ErrBotAccount,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			querier := classifyingQuerier{querier: newFixtureQuerier(t, "accounts")}
+
+			identity, err := lookupUser(context.Background(), querier, testCase.userName, knownUsers)
+			var renamedErr *UserRenamedError
+			switch {
+			case testCase.expectedNewLogin != "":
+				if !errors.As(err, &renamedErr) {
+					t.Fatalf("expected rename, got %v", err)
+				}
+				if renamedErr.NewLogin != testCase.expectedNewLogin {
+					t.Errorf("new login: got %q, want %q", renamedErr.NewLogin, testCase.expectedNewLogin)
+				}
+			case testCase.expectedErr != nil:
+				if !errors.Is(err, testCase.expectedErr) {
+					t.Fatalf("expected %v, got %v", testCase.expectedErr, err)
+				}
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if identity != testCase.expectedIdentity {
+				t.Errorf("identity: got %+v, want %+v", identity, testCase.expectedIdentity)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"strings"
+)
+
+// ActivityType is a kind of contribution that is counted in a report.
+type ActivityType string
+
+const (
+	IssuesCreatedActivity         ActivityType = "issuesCreated"
+	IssuesCommentedActivity       ActivityType = "issuesCommented"
+	PullRequestsCreatedActivity   ActivityType = "pullRequestsCreated"
+	PullRequestsReviewedActivity  ActivityType = "pullRequestsReviewed"
+	PullRequestsCommentedActivity ActivityType = "pullRequestsCommented"
+	CommitsActivity               ActivityType = "commits"
+	DiscussionsCreatedActivity    ActivityType = "discussionsCreated"
+	DiscussionsAnsweredActivity   ActivityType = "discussionsAnswered"
+	DiscussionsCommentedActivity  ActivityType = "discussionsCommented"
+	ReleasesCreatedActivity       ActivityType = "releasesCreated"
+	MailsSentActivity             ActivityType = "mailsSent"
+	MailThreadsStartedActivity    ActivityType = "mailThreadsStarted"
+	SlackMessagesActivity         ActivityType = "slackMessages"
+	SlackThreadsAnsweredActivity  ActivityType = "slackThreadsAnswered"
+	LGTMCommandsActivity          ActivityType = "lgtmCommands"
+	ApproveCommandsActivity       ActivityType = "approveCommands"
+	HoldCommandsActivity          ActivityType = "holdCommands"
+	RetestCommandsActivity        ActivityType = "retestCommands"
+)
+
+// ActivityTypes holds all activity types in the order of presentation.
+var ActivityTypes = []ActivityType{
+	IssuesCreatedActivity,
+	IssuesCommentedActivity,
+	PullRequestsCreatedActivity,
+	PullRequestsReviewedActivity,
+	PullRequestsCommentedActivity,
+	CommitsActivity,
+	DiscussionsCreatedActivity,
+	DiscussionsAnsweredActivity,
+	DiscussionsCommentedActivity,
+	ReleasesCreatedActivity,
+	MailsSentActivity,
+	MailThreadsStartedActivity,
+	SlackMessagesActivity,
+	SlackThreadsAnsweredActivity,
+	LGTMCommandsActivity,
+	ApproveCommandsActivity,
+	HoldCommandsActivity,
+	RetestCommandsActivity,
+}
+
+// ActivityCounts holds the number of contributions per activity type.
+type ActivityCounts map[ActivityType]int
+
+// Add adds the counts of other to the counts.
+func (a ActivityCounts) Add(other ActivityCounts) {
+	for activityType, count := range other {
+		a[activityType] += count
+	}
+}
+
+// Total returns the sum of all counts.
+func (a ActivityCounts) Total() int {
+	total := 0
+	for _, count := range a {
+		total += count
+	}
+	return total
+}
+
+func (u *UserContributions) activityCounts(userName string, window ReportWindow) ActivityCounts {
+	return ActivityCounts{
+		IssuesCreatedActivity:         u.IssuesCreated.IssueCount,
+		IssuesCommentedActivity:       u.IssuesCommented.IssueCount,
+		PullRequestsCreatedActivity:   u.PullRequestsCreated.IssueCount,
+		PullRequestsReviewedActivity:  u.PullRequestsReviewed.IssueCount,
+		PullRequestsCommentedActivity: u.PullRequestsCommented.IssueCount,
+		DiscussionsCreatedActivity:    u.DiscussionsCreated.IssueCount,
+		DiscussionsAnsweredActivity:   len(u.discussionsAnswered(userName, window)),
+		DiscussionsCommentedActivity:  u.DiscussionsCommented.IssueCount,
+		ReleasesCreatedActivity:       u.ReleasesCreated.TotalCount,
+	}
+}
+
+// discussionsAnswered returns the URLs of the discussions that the user
+// commented on, where a comment of the user has been marked as the answer
+// within the window.
+func (u *UserContributions) discussionsAnswered(userName string, window ReportWindow) []string {
+	var urls []string
+	for _, node := range u.DiscussionsCommented.Nodes {
+		answer := node.Discussion.Answer
+		if answer != nil && strings.EqualFold(answer.Author.Login, userName) && window.contains(answer.CreatedAt) {
+			urls = append(urls, node.Discussion.URL)
+		}
+	}
+	return urls
+}
@@ -0,0 +1,260 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+package contributions
+
+import (
+	"bufio"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// UnknownCompany is the company of users that are not listed in the
+// affiliations, or whose listed affiliations have ended.
+const UnknownCompany = "(Unknown)"
+
+// Affiliation is the employment of a user at a company, which ends at
+// Until (exclusive). A zero Until means that the affiliation is current.
+type Affiliation struct {
+	Company string    `yaml:"company"`
+	Until   time.Time `yaml:"until,omitempty"`
+}
+
+// Affiliations holds the affiliations of users keyed by lower case login,
+// in chronological order.
+type Affiliations map[string][]Affiliation
+
+// ReadAffiliations reads the affiliations from a file in the format of
+// gitdm's developers_affiliations.txt.
+func ReadAffiliations(path string) (Affiliations, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open affiliations file: %v", err)
+	}
+	defer file.Close()
+	affiliations, err := ParseAffiliations(file)
+	if err != nil {
+		return nil, fmt.Errorf("in file %q: %v", path, err)
+	}
+	return affiliations, nil
+}
+
+// ParseAffiliations parses affiliations in the format of gitdm's
+// developers_affiliations.txt, where a line with the login of a user
+// (optionally followed by a colon and the mail addresses of the user) is
+// followed by indented lines with the companies the user worked for,
+// oldest first, each but the current one ending with `until <date>`:
+//
+//	janedoe: jane!example.com
+//		Red Hat until 2023-04-01
+//		NVIDIA
+func ParseAffiliations(r io.Reader) (Affiliations, error) {
+	affiliations := make(Affiliations)
+	var login string
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if trimmed == line {
+			login, _, _ = strings.Cut(trimmed, ":")
+			login = strings.ToLower(strings.TrimSpace(login))
+			continue
+		}
+		if login == "" {
+			return nil, fmt.Errorf("line %d: affiliation without user", lineNumber)
+		}
+		affiliation := Affiliation{Company: trimmed}
+		if company, until, found := strings.Cut(trimmed, " until "); found {
+			untilDate, err := time.Parse(time.DateOnly, strings.TrimSpace(until))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid until date: %v", lineNumber, err)
+			}
+			affiliation = Affiliation{Company: strings.TrimSpace(company), Until: untilDate}
+		}
+		affiliations[login] = append(affiliations[login], affiliation)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return affiliations, nil
+}
+
+// CompanyPeriod is a part of a report window during which a user has been
+// affiliated with the same company.
+type CompanyPeriod struct {
+	Company string
+	Window  ReportWindow
+}
+
+// Periods splits the window into the periods of the affiliations of the
+// user, where adjacent periods at the same company are joined. Times that
+// are not covered by an affiliation are attributed to UnknownCompany.
+func (a Affiliations) Periods(userName string, window ReportWindow) []CompanyPeriod {
+	var periods []CompanyPeriod
+	addPeriod := func(company string, since, until time.Time) {
+		if !since.Before(until) {
+			return
+		}
+		if last := len(periods) - 1; last >= 0 && periods[last].Company == company {
+			periods[last].Window.Until = until
+			return
+		}
+		periods = append(periods, CompanyPeriod{Company: company, Window: ReportWindow{Since: since, Until: until}})
+	}
+	since := window.Since
+	for _, affiliation := range a[strings.ToLower(userName)] {
+		until := window.Until
+		if !affiliation.Until.IsZero() && affiliation.Until.Before(until) {
+			until = affiliation.Until
+		}
+		addPeriod(affiliation.Company, since, until)
+		if until.After(since) {
+			since = until
+		}
+		if affiliation.Until.IsZero() {
+			since = window.Until
+		}
+	}
+	addPeriod(UnknownCompany, since, window.Until)
+	return periods
+}
+
+// CompanyActivity holds the contributions of the users affiliated with a
+// company.
+type CompanyActivity struct {
+	Company        string         `yaml:"company"`
+	Users          []string       `yaml:"users"`
+	ActivityCounts ActivityCounts `yaml:"activityCounts"`
+}
+
+// AffiliationBreakdown holds the contributions within a scope and window
+// per company, ordered by the total number of contributions.
+type AffiliationBreakdown struct {
+	Scope     string            `yaml:"scope"`
+	Window    ReportWindow      `yaml:"window"`
+	Companies []CompanyActivity `yaml:"companies"`
+}
+
+func NewAffiliationBreakdown(scope string, window ReportWindow) *AffiliationBreakdown {
+	return &AffiliationBreakdown{Scope: scope, Window: window, Companies: []CompanyActivity{}}
+}
+
+// Add attributes the contributions of the user to the company, where users
+// without contributions are not counted.
+func (b *AffiliationBreakdown) Add(company, userName string, activityCounts ActivityCounts) {
+	if activityCounts.Total() == 0 {
+		return
+	}
+	var companyActivity *CompanyActivity
+	for i := range b.Companies {
+		if b.Companies[i].Company == company {
+			companyActivity = &b.Companies[i]
+			break
+		}
+	}
+	if companyActivity == nil {
+		b.Companies = append(b.Companies, CompanyActivity{Company: company, ActivityCounts: ActivityCounts{}})
+		companyActivity = &b.Companies[len(b.Companies)-1]
+	}
+	if !containsFold(companyActivity.Users, userName) {
+		companyActivity.Users = append(companyActivity.Users, userName)
+		sort.Strings(companyActivity.Users)
+	}
+	companyActivity.ActivityCounts.Add(activityCounts)
+	sort.SliceStable(b.Companies, func(i, j int) bool {
+		if b.Companies[i].ActivityCounts.Total() != b.Companies[j].ActivityCounts.Total() {
+			return b.Companies[i].ActivityCounts.Total() > b.Companies[j].ActivityCounts.Total()
+		}
+		return b.Companies[i].Company < b.Companies[j].Company
+	})
+}
+
+func containsFold(elements []string, element string) bool {
+	for _, e := range elements {
+		if strings.EqualFold(e, element) {
+			return true
+		}
+	}
+	return false
+}
+
+// Format writes the breakdown as YAML, or as Markdown tables with the
+// share of each company and the activity counts per company.
+func (b *AffiliationBreakdown) Format(w io.Writer, format Format) error {
+	switch format {
+	case YAMLFormat:
+		return yaml.NewEncoder(w).Encode(b)
+	case MarkdownFormat:
+		return b.formatMarkdown(w)
+	}
+	return fmt.Errorf("format %q is not supported for affiliations", format)
+}
+
+func (b *AffiliationBreakdown) formatMarkdown(w io.Writer) error {
+	total := 0
+	for _, company := range b.Companies {
+		total += company.ActivityCounts.Total()
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "## Contributions per company in %s\n\n", b.Scope)
+	fmt.Fprintf(&out, "%s to %s\n\n", b.Window.Since.Format(time.DateOnly), b.Window.lastDay().Format(time.DateOnly))
+	out.WriteString("| company | contributors | contributions | share |\n")
+	out.WriteString("|---|--:|--:|--:|\n")
+	for _, company := range b.Companies {
+		share := 0.0
+		if total > 0 {
+			share = 100 * float64(company.ActivityCounts.Total()) / float64(total)
+		}
+		fmt.Fprintf(&out, "| %s | %d | %d | %.1f%% |\n", company.Company, len(company.Users), company.ActivityCounts.Total(), share)
+	}
+	if len(b.Companies) == 0 {
+		_, err := io.WriteString(w, out.String())
+		return err
+	}
+
+	out.WriteString("\n| activity |")
+	for _, company := range b.Companies {
+		fmt.Fprintf(&out, " %s |", company.Company)
+	}
+	fmt.Fprintf(&out, "\n|---%s|\n", strings.Repeat("|--:", len(b.Companies)))
+	for _, activityType := range ActivityTypes {
+		fmt.Fprintf(&out, "| %s |", activityType)
+		for _, company := range b.Companies {
+			fmt.Fprintf(&out, " %d |", company.ActivityCounts[activityType])
+		}
+		out.WriteString("\n")
+	}
+	out.WriteString("| **total** |")
+	for _, company := range b.Companies {
+		fmt.Fprintf(&out, " **%d** |", company.ActivityCounts.Total())
+	}
+	out.WriteString("\n")
+	_, err := io.WriteString(w, out.String())
+	return err
+}
@@ -0,0 +1,184 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+package contributions
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAffiliationsPeriods(t *testing.T) {
+	affiliations, err := ReadAffiliations("testdata/developers_affiliations.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	date := func(month, day int) time.Time {
+		return time.Date(2024, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	}
+	window := ReportWindow{Since: date(10, 1), Until: date(12, 31)}
+	testCases := []struct {
+		name            string
+		userName        string
+		window          ReportWindow
+		expectedPeriods []CompanyPeriod
+	}{
+		{
+			name:     "changed company within window",
+			userName: "TestUser",
+			window:   window,
+			expectedPeriods: []CompanyPeriod{
+				{Company: "Red Hat", Window: ReportWindow{Since: date(10, 1), Until: date(11, 15)}},
+				{Company: "NVIDIA", Window: ReportWindow{Since: date(11, 15), Until: date(12, 31)}},
+			},
+		},
+		{
+			name:     "changed company before window",
+			userName: "testuser",
+			window:   ReportWindow{Since: date(12, 1), Until: date(12, 31)},
+			expectedPeriods: []CompanyPeriod{
+				{Company: "NVIDIA", Window: ReportWindow{Since: date(12, 1), Until: date(12, 31)}},
+			},
+		},
+		{
+			name:     "single company",
+			userName: "otheruser",
+			window:   window,
+			expectedPeriods: []CompanyPeriod{
+				{Company: "SUSE", Window: window},
+			},
+		},
+		{
+			name:     "affiliation ended within window",
+			userName: "formeruser",
+			window:   window,
+			expectedPeriods: []CompanyPeriod{
+				{Company: "IBM", Window: ReportWindow{Since: date(10, 1), Until: date(10, 15)}},
+				{Company: UnknownCompany, Window: ReportWindow{Since: date(10, 15), Until: date(12, 31)}},
+			},
+		},
+		{
+			name:     "unknown user",
+			userName: "unknown",
+			window:   window,
+			expectedPeriods: []CompanyPeriod{
+				{Company: UnknownCompany, Window: window},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			periods := affiliations.Periods(testCase.userName, testCase.window)
+			if !reflect.DeepEqual(periods, testCase.expectedPeriods) {
+				t.Errorf("got %+v, want %+v", periods, testCase.expectedPeriods)
+			}
+		})
+	}
+}
+
+func TestParseAffiliationsInvalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "affiliation without user",
+			content: "\tRed Hat\n",
+		},
+		{
+			name:    "invalid until date",
+			content: "testuser: test!example.com\n\tRed Hat until last year\n",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := ParseAffiliations(strings.NewReader(testCase.content))
+			if err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+func TestAffiliationBreakdown(t *testing.T) {
+	window := ReportWindow{
+		Since: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	breakdown := NewAffiliationBreakdown("kubevirt", window)
+	breakdown.Add("Red Hat", "testuser", ActivityCounts{PullRequestsCreatedActivity: 1})
+	breakdown.Add("NVIDIA", "testuser", ActivityCounts{PullRequestsReviewedActivity: 2, CommitsActivity: 1})
+	breakdown.Add("Red Hat", "otheruser", ActivityCounts{CommitsActivity: 1})
+	breakdown.Add("SUSE", "inactiveuser", ActivityCounts{})
+
+	expectedCompanies := []CompanyActivity{
+		{Company: "NVIDIA", Users: []string{"testuser"}, ActivityCounts: ActivityCounts{PullRequestsReviewedActivity: 2, CommitsActivity: 1}},
+		{Company: "Red Hat", Users: []string{"otheruser", "testuser"}, ActivityCounts: ActivityCounts{PullRequestsCreatedActivity: 1, CommitsActivity: 1}},
+	}
+	if !reflect.DeepEqual(breakdown.Companies, expectedCompanies) {
+		t.Errorf("companies: got %+v, want %+v", breakdown.Companies, expectedCompanies)
+	}
+
+	var out bytes.Buffer
+	err := breakdown.Format(&out, MarkdownFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expected := range []string{
+		"## Contributions per company in kubevirt",
+		"2024-10-01 to 2024-12-31",
+		"| NVIDIA | 1 | 3 | 60.0% |",
+		"| Red Hat | 2 | 2 | 40.0% |",
+		"| activity | NVIDIA | Red Hat |",
+		"| commits | 1 | 1 |",
+		"| **total** | **3** | **2** |",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q in output:\n%s", expected, out.String())
+		}
+	}
+	err = breakdown.Format(&out, CSVFormat)
+	if err == nil {
+		t.Errorf("expected error for unsupported format")
+	}
+}
+
+func TestGenerateReportInWindow(t *testing.T) {
+	generator := newTestGenerator(t, "repository", ContributionReportGeneratorOptions{
+		Org:    "kubevirt",
+		Repo:   "community",
+		Months: 6,
+	})
+	window := ReportWindow{
+		Since: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	report, err := generator.GenerateReportInWindow(context.Background(), "testuser", window)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repoReport := report.(*UserContributionReportForRepository)
+	if repoReport.Window != window {
+		t.Errorf("window: got %v, want %v", repoReport.Window, window)
+	}
+}
@@ -0,0 +1,367 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// reportCache stores the raw query results of contribution reports on disk.
+// Entries are keyed by user and org (and repo), and hold the window that
+// has been fetched, so that a later run only needs to fetch the activity
+// that happened after the entry was stored.
+type reportCache struct {
+	dir string
+}
+
+// cacheKey identifies a cache entry. includeArchived only applies to
+// organization reports. commitEmails are the additional emails that the
+// commits of the user were queried with.
+type cacheKey struct {
+	org             string
+	repo            string
+	userName        string
+	includeArchived bool
+	commitEmails    []string
+}
+
+// cacheEntry holds the report over the fetched window, where the end of
+// the window is the time up to which the activity has been fetched.
+type cacheEntry struct {
+	Window             ReportWindow                           `yaml:"window"`
+	RepositoryReport   *UserContributionReportForRepository   `yaml:"repositoryReport,omitempty"`
+	OrganizationReport *UserContributionReportForOrganization `yaml:"organizationReport,omitempty"`
+}
+
+func (e *cacheEntry) report() ContributionReport {
+	if e.RepositoryReport != nil {
+		return e.RepositoryReport
+	}
+	return e.OrganizationReport
+}
+
+// trimmed returns a copy of the entry that only holds the nodes within the
+// window, the entry itself is not modified.
+func (e *cacheEntry) trimmed(window ReportWindow) *cacheEntry {
+	trimmed := &cacheEntry{Window: window}
+	if e.RepositoryReport != nil {
+		trimmed.RepositoryReport = e.RepositoryReport.trimmed(window)
+	}
+	if e.OrganizationReport != nil {
+		trimmed.OrganizationReport = e.OrganizationReport.trimmed(window)
+	}
+	return trimmed
+}
+
+func (c reportCache) path(key cacheKey) string {
+	name := strings.ToLower(key.userName)
+	if key.includeArchived {
+		name += "-archived"
+	}
+	if len(key.commitEmails) > 0 {
+		hash := fnv.New32a()
+		for _, email := range key.commitEmails {
+			hash.Write([]byte(strings.ToLower(email) + "\n"))
+		}
+		name += fmt.Sprintf("-%08x", hash.Sum32())
+	}
+	fileName := name + ".yaml"
+	if key.repo != "" {
+		return filepath.Join(c.dir, key.org, key.repo, fileName)
+	}
+	return filepath.Join(c.dir, key.org, fileName)
+}
+
+// load returns the cache entry for the key, or nil if there is none.
+func (c reportCache) load(key cacheKey) (*cacheEntry, error) {
+	path := c.path(key)
+	buf, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading cache entry %s: %v", path, err)
+	}
+	entry := &cacheEntry{}
+	err = yaml.Unmarshal(buf, entry)
+	if err != nil {
+		return nil, fmt.Errorf("in cache entry %q: %v", path, err)
+	}
+	if entry.report() == nil {
+		return nil, fmt.Errorf("cache entry %q does not contain a report", path)
+	}
+	return entry, nil
+}
+
+func (c reportCache) store(key cacheKey, entry *cacheEntry) error {
+	path := c.path(key)
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create cache dir: %v", err)
+	}
+	buf, err := yaml.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %v", err)
+	}
+	// write to a temporary file first, so that an interrupted write does not
+	// leave a broken entry behind
+	tempPath := path + ".tmp"
+	err = os.WriteFile(tempPath, buf, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry %s: %v", tempPath, err)
+	}
+	return os.Rename(tempPath, path)
+}
+
+// mergeNodes returns the newer nodes followed by those older nodes that
+// are not contained in the newer nodes, together with the number of older
+// nodes that were replaced. Nodes are identified by key.
+func mergeNodes[T any](older, newer []T, key func(T) string) ([]T, int) {
+	newerKeys := make(map[string]struct{}, len(newer))
+	for _, node := range newer {
+		newerKeys[key(node)] = struct{}{}
+	}
+	merged := append([]T{}, newer...)
+	duplicates := 0
+	for _, node := range older {
+		if _, exists := newerKeys[key(node)]; exists {
+			duplicates++
+			continue
+		}
+		merged = append(merged, node)
+	}
+	return merged, duplicates
+}
+
+func mergeSearchConnections[T any](older, newer searchConnection[T], key func(T) string) searchConnection[T] {
+	nodes, duplicates := mergeNodes(older.Nodes, newer.Nodes, key)
+	return searchConnection[T]{
+		IssueCount: older.IssueCount + newer.IssueCount - duplicates,
+		PageInfo: PageInfo{
+			HasNextPage: older.PageInfo.HasNextPage || newer.PageInfo.HasNextPage,
+			EndCursor:   newer.PageInfo.EndCursor,
+		},
+		Nodes: nodes,
+	}
+}
+
+func (u *UserContributions) merge(newer UserContributions) {
+	u.IssuesCreated = IssuesCreated(mergeSearchConnections(
+		searchConnection[IssuesCreatedNodeItem](u.IssuesCreated),
+		searchConnection[IssuesCreatedNodeItem](newer.IssuesCreated),
+		func(node IssuesCreatedNodeItem) string { return node.Issue.URL },
+	))
+	u.IssuesCommented = IssuesCommented(mergeSearchConnections(
+		searchConnection[IssuesCommentedNodeItem](u.IssuesCommented),
+		searchConnection[IssuesCommentedNodeItem](newer.IssuesCommented),
+		func(node IssuesCommentedNodeItem) string { return node.Issue.URL },
+	))
+	u.PullRequestsCreated = PullRequestsCreated(mergeSearchConnections(
+		searchConnection[PullRequestNodeItem](u.PullRequestsCreated),
+		searchConnection[PullRequestNodeItem](newer.PullRequestsCreated),
+		func(node PullRequestNodeItem) string { return node.PullRequest.URL },
+	))
+	u.PullRequestsReviewed = PullRequestsReviewed(mergeSearchConnections(
+		searchConnection[PullRequestReviewNodeItem](u.PullRequestsReviewed),
+		searchConnection[PullRequestReviewNodeItem](newer.PullRequestsReviewed),
+		func(node PullRequestReviewNodeItem) string { return node.PullRequestReview.URL },
+	))
+	u.PullRequestsCommented = PullRequestsCommented(mergeSearchConnections(
+		searchConnection[PullRequestCommentedItem](u.PullRequestsCommented),
+		searchConnection[PullRequestCommentedItem](newer.PullRequestsCommented),
+		func(node PullRequestCommentedItem) string { return node.PullRequest.URL },
+	))
+	u.DiscussionsCreated = DiscussionsCreated(mergeSearchConnections(
+		searchConnection[DiscussionNodeItem](u.DiscussionsCreated),
+		searchConnection[DiscussionNodeItem](newer.DiscussionsCreated),
+		func(node DiscussionNodeItem) string { return node.Discussion.URL },
+	))
+	u.DiscussionsCommented = DiscussionsCommented(mergeSearchConnections(
+		searchConnection[DiscussionNodeItem](u.DiscussionsCommented),
+		searchConnection[DiscussionNodeItem](newer.DiscussionsCommented),
+		func(node DiscussionNodeItem) string { return node.Discussion.URL },
+	))
+	releases, duplicates := mergeNodes(u.ReleasesCreated.Nodes, newer.ReleasesCreated.Nodes, func(node ReleaseNode) string {
+		return node.URL
+	})
+	u.ReleasesCreated.TotalCount += newer.ReleasesCreated.TotalCount - duplicates
+	u.ReleasesCreated.Nodes = releases
+}
+
+// merge adds the commits of newer that are not contained yet.
+func (c *CommitsByUser) merge(newer CommitsByUser) {
+	history := &c.DefaultBranchRef.Target.Fragment.History
+	newerHistory := newer.DefaultBranchRef.Target.Fragment.History
+	nodes, duplicates := mergeNodes(history.Nodes, newerHistory.Nodes, func(node CommitsByUserTargetHistoryNode) string {
+		return node.CommitUrl
+	})
+	history.TotalCount += newerHistory.TotalCount - duplicates
+	history.PageInfo.HasNextPage = history.PageInfo.HasNextPage || newerHistory.PageInfo.HasNextPage
+	history.Nodes = nodes
+}
+
+func (u *UserContributionReportForRepository) merge(newer *UserContributionReportForRepository) {
+	u.UserContributions.merge(newer.UserContributions)
+	u.CommitsByUser.merge(newer.CommitsByUser)
+	u.UserID = newer.UserID
+	u.UserDatabaseID = newer.UserDatabaseID
+	u.Window.Until = newer.Window.Until
+}
+
+// merge adds the commits of newer that are not contained yet, per
+// repository.
+func (c *CommitsByUserInOrg) merge(newer CommitsByUserInOrg) {
+	repositories := make(map[string]*RepositoryNode)
+	for i := range c.Repositories.Nodes {
+		repository := &c.Repositories.Nodes[i]
+		repositories[repository.Name] = repository
+	}
+	var addedRepositories []RepositoryNode
+	for _, newerRepository := range newer.Repositories.Nodes {
+		repository, exists := repositories[newerRepository.Name]
+		if !exists {
+			addedRepositories = append(addedRepositories, newerRepository)
+			continue
+		}
+		history := &repository.DefaultBranchRef.Target.Fragment.History
+		newerHistory := newerRepository.DefaultBranchRef.Target.Fragment.History
+		nodes, duplicates := mergeNodes(history.Nodes, newerHistory.Nodes, func(node RepositoryNodeRefTargetHistoryNode) string {
+			return node.URL
+		})
+		history.TotalCount += newerHistory.TotalCount - duplicates
+		history.PageInfo.HasNextPage = history.PageInfo.HasNextPage || newerHistory.PageInfo.HasNextPage
+		history.Nodes = nodes
+	}
+	c.Repositories.Nodes = append(c.Repositories.Nodes, addedRepositories...)
+}
+
+func (u *UserContributionReportForOrganization) merge(newer *UserContributionReportForOrganization) {
+	u.UserContributions.merge(newer.UserContributions)
+	u.CommitsByUserInOrg.merge(newer.CommitsByUserInOrg)
+	u.UserID = newer.UserID
+	u.UserDatabaseID = newer.UserDatabaseID
+	u.Window.Until = newer.Window.Until
+}
+
+// trimNodes returns the nodes whose time is contained, together with the
+// number of nodes that were removed. Nodes without a time are kept, since
+// they can't be placed in the window.
+func trimNodes[T any](nodes []T, contains func(time.Time) bool, at func(T) time.Time) ([]T, int) {
+	var trimmed []T
+	for _, node := range nodes {
+		nodeTime := at(node)
+		if nodeTime.IsZero() || contains(nodeTime) {
+			trimmed = append(trimmed, node)
+		}
+	}
+	return trimmed, len(nodes) - len(trimmed)
+}
+
+func trimSearchConnection[T any](connection searchConnection[T], window ReportWindow, at func(T) time.Time) searchConnection[T] {
+	nodes, removed := trimNodes(connection.Nodes, window.searchContains, at)
+	connection.IssueCount -= removed
+	connection.Nodes = nodes
+	return connection
+}
+
+// trimmed returns the contributions within the window, where each search
+// result is kept by the same rule the search matches it with, that is by
+// the `created` or `updated` time of the node on the days of the window.
+// This way the trimmed contributions match those of a search over the
+// window.
+func (u *UserContributions) trimmed(window ReportWindow) UserContributions {
+	trimmed := *u
+	trimmed.IssuesCreated = IssuesCreated(trimSearchConnection(
+		searchConnection[IssuesCreatedNodeItem](trimmed.IssuesCreated), window,
+		func(node IssuesCreatedNodeItem) time.Time { return node.Issue.CreatedAt },
+	))
+	trimmed.IssuesCommented = IssuesCommented(trimSearchConnection(
+		searchConnection[IssuesCommentedNodeItem](trimmed.IssuesCommented), window,
+		func(node IssuesCommentedNodeItem) time.Time { return node.Issue.CreatedAt },
+	))
+	trimmed.PullRequestsCreated = PullRequestsCreated(trimSearchConnection(
+		searchConnection[PullRequestNodeItem](trimmed.PullRequestsCreated), window,
+		func(node PullRequestNodeItem) time.Time { return node.PullRequest.CreatedAt },
+	))
+	trimmed.PullRequestsReviewed = PullRequestsReviewed(trimSearchConnection(
+		searchConnection[PullRequestReviewNodeItem](trimmed.PullRequestsReviewed), window,
+		func(node PullRequestReviewNodeItem) time.Time { return node.PullRequestReview.UpdatedAt },
+	))
+	trimmed.PullRequestsCommented = PullRequestsCommented(trimSearchConnection(
+		searchConnection[PullRequestCommentedItem](trimmed.PullRequestsCommented), window,
+		func(node PullRequestCommentedItem) time.Time { return node.PullRequest.UpdatedAt },
+	))
+	trimmed.DiscussionsCreated = DiscussionsCreated(trimSearchConnection(
+		searchConnection[DiscussionNodeItem](trimmed.DiscussionsCreated), window,
+		func(node DiscussionNodeItem) time.Time { return node.Discussion.CreatedAt },
+	))
+	trimmed.DiscussionsCommented = DiscussionsCommented(trimSearchConnection(
+		searchConnection[DiscussionNodeItem](trimmed.DiscussionsCommented), window,
+		func(node DiscussionNodeItem) time.Time { return node.Discussion.UpdatedAt },
+	))
+	releases, removed := trimNodes(trimmed.ReleasesCreated.Nodes, window.contains, func(node ReleaseNode) time.Time {
+		return node.PublishedAt
+	})
+	trimmed.ReleasesCreated.TotalCount -= removed
+	trimmed.ReleasesCreated.Nodes = releases
+	return trimmed
+}
+
+func (u *UserContributionReportForRepository) trimmed(window ReportWindow) *UserContributionReportForRepository {
+	trimmed := *u
+	trimmed.UserContributions = u.UserContributions.trimmed(window)
+	history := &trimmed.CommitsByUser.DefaultBranchRef.Target.Fragment.History
+	nodes, removed := trimNodes(history.Nodes, window.contains, func(node CommitsByUserTargetHistoryNode) time.Time {
+		return node.CommittedDate
+	})
+	history.TotalCount -= removed
+	history.Nodes = nodes
+	trimmed.Window = window
+	return &trimmed
+}
+
+// trimmed returns the report within the window, where repositories without
+// commits within the window are dropped.
+func (u *UserContributionReportForOrganization) trimmed(window ReportWindow) *UserContributionReportForOrganization {
+	trimmed := *u
+	trimmed.UserContributions = u.UserContributions.trimmed(window)
+	trimmed.CommitsByUserInOrg.Repositories.Nodes = nil
+	for _, repository := range u.CommitsByUserInOrg.Repositories.Nodes {
+		history := &repository.DefaultBranchRef.Target.Fragment.History
+		nodes, removed := trimNodes(history.Nodes, window.contains, func(node RepositoryNodeRefTargetHistoryNode) time.Time {
+			return node.CommittedDate
+		})
+		history.TotalCount -= removed
+		history.Nodes = nodes
+		if history.TotalCount == 0 {
+			continue
+		}
+		trimmed.CommitsByUserInOrg.Repositories.Nodes = append(trimmed.CommitsByUserInOrg.Repositories.Nodes, repository)
+	}
+	trimmed.Window = window
+	return &trimmed
+}
@@ -0,0 +1,187 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMergeNodes(t *testing.T) {
+	testCases := []struct {
+		name               string
+		older              []string
+		newer              []string
+		expectedMerged     []string
+		expectedDuplicates int
+	}{
+		{
+			name:               "no older nodes",
+			newer:              []string{"a", "b"},
+			expectedMerged:     []string{"a", "b"},
+			expectedDuplicates: 0,
+		},
+		{
+			name:               "disjoint nodes",
+			older:              []string{"a", "b"},
+			newer:              []string{"c"},
+			expectedMerged:     []string{"c", "a", "b"},
+			expectedDuplicates: 0,
+		},
+		{
+			name:               "overlapping nodes",
+			older:              []string{"a", "b"},
+			newer:              []string{"b", "c"},
+			expectedMerged:     []string{"b", "c", "a"},
+			expectedDuplicates: 1,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			merged, duplicates := mergeNodes(testCase.older, testCase.newer, func(node string) string { return node })
+			if !reflect.DeepEqual(merged, testCase.expectedMerged) {
+				t.Errorf("merged: got %v, want %v", merged, testCase.expectedMerged)
+			}
+			if duplicates != testCase.expectedDuplicates {
+				t.Errorf("duplicates: got %d, want %d", duplicates, testCase.expectedDuplicates)
+			}
+		})
+	}
+}
+
+func TestGenerateReportWithCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	opts := ContributionReportGeneratorOptions{
+		Org:      "kubevirt",
+		Repo:     "community",
+		Since:    time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		CacheDir: cacheDir,
+	}
+	generator := newTestGenerator(t, "repository", opts)
+
+	// the second run fetches the same nodes again, which must not get
+	// counted twice
+	for run := 1; run <= 2; run++ {
+		report, err := generator.GenerateReport(context.Background(), "testuser")
+		if err != nil {
+			t.Fatalf("run %d: unexpected error: %v", run, err)
+		}
+		repoReport := report.(*UserContributionReportForRepository)
+		if repoReport.IssuesCreated.IssueCount != 2 || len(repoReport.IssuesCreated.Nodes) != 2 {
+			t.Errorf("run %d: issues created: got %d with %d nodes, want 2 with 2 nodes", run, repoReport.IssuesCreated.IssueCount, len(repoReport.IssuesCreated.Nodes))
+		}
+		if repoReport.CommitsByUser.DefaultBranchRef.Target.Fragment.History.TotalCount != 2 {
+			t.Errorf("run %d: commits: got %d, want 2", run, repoReport.CommitsByUser.DefaultBranchRef.Target.Fragment.History.TotalCount)
+		}
+	}
+
+	testCases := []struct {
+		name                        string
+		since                       time.Time
+		expectedIssuesCreated       int
+		expectedPullRequestsCreated int
+		expectedCommits             int
+	}{
+		{
+			name:                        "same window",
+			since:                       opts.Since,
+			expectedIssuesCreated:       2,
+			expectedPullRequestsCreated: 1,
+			expectedCommits:             2,
+		},
+		{
+			name:                        "later start of the window",
+			since:                       time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+			expectedIssuesCreated:       2,
+			expectedPullRequestsCreated: 0,
+			expectedCommits:             0,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cacheOnlyOpts := opts
+			cacheOnlyOpts.Since = testCase.since
+			cacheOnlyOpts.FromCache = true
+			cacheOnlyGenerator, err := NewContributionReportGenerator(cacheOnlyOpts)
+			if err != nil {
+				t.Fatalf("failed to create generator: %v", err)
+			}
+			report, err := cacheOnlyGenerator.GenerateReport(context.Background(), "testuser")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			repoReport := report.(*UserContributionReportForRepository)
+			if repoReport.IssuesCreated.IssueCount != testCase.expectedIssuesCreated || len(repoReport.IssuesCreated.Nodes) != testCase.expectedIssuesCreated {
+				t.Errorf("issues created: got %d with %d nodes, want %d", repoReport.IssuesCreated.IssueCount, len(repoReport.IssuesCreated.Nodes), testCase.expectedIssuesCreated)
+			}
+			if repoReport.PullRequestsCreated.IssueCount != testCase.expectedPullRequestsCreated {
+				t.Errorf("pull requests created: got %d, want %d", repoReport.PullRequestsCreated.IssueCount, testCase.expectedPullRequestsCreated)
+			}
+			if repoReport.CommitsByUser.DefaultBranchRef.Target.Fragment.History.TotalCount != testCase.expectedCommits {
+				t.Errorf("commits: got %d, want %d", repoReport.CommitsByUser.DefaultBranchRef.Target.Fragment.History.TotalCount, testCase.expectedCommits)
+			}
+			if !repoReport.Window.Since.Equal(testCase.since) {
+				t.Errorf("window: got start %s, want %s", repoReport.Window.Since, testCase.since)
+			}
+			_, err = cacheOnlyGenerator.GenerateReport(context.Background(), "uncacheduser")
+			if err == nil {
+				t.Errorf("expected error for user that is not cached")
+			}
+		})
+	}
+}
+
+func TestGenerateReportWithCacheMatchesUncachedReport(t *testing.T) {
+	opts := ContributionReportGeneratorOptions{
+		Org:   "kubevirt",
+		Repo:  "community",
+		Since: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+	}
+	uncachedReport, err := newTestGenerator(t, "cache", opts).GenerateReport(context.Background(), "testuser")
+	if err != nil {
+		t.Fatalf("uncached run: unexpected error: %v", err)
+	}
+
+	// the first run caches the report up to the start of November, before
+	// the user commented on an issue that was created in October, the
+	// second run updates the cached report over the rest of the window
+	cachedOpts := opts
+	cachedOpts.CacheDir = t.TempDir()
+	cachedOpts.Until = time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	_, err = newTestGenerator(t, "cache", cachedOpts).GenerateReport(context.Background(), "testuser")
+	if err != nil {
+		t.Fatalf("first cached run: unexpected error: %v", err)
+	}
+	cachedOpts.Until = opts.Until
+	cachedReport, err := newTestGenerator(t, "cache", cachedOpts).GenerateReport(context.Background(), "testuser")
+	if err != nil {
+		t.Fatalf("second cached run: unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(cachedReport.ActivityCounts(), uncachedReport.ActivityCounts()) {
+		t.Errorf("activity counts: got %v from the cache, want %v", cachedReport.ActivityCounts(), uncachedReport.ActivityCounts())
+	}
+	if cachedReport.ActivityCounts()[IssuesCommentedActivity] != 1 {
+		t.Errorf("issues commented: got %d, want 1", cachedReport.ActivityCounts()[IssuesCommentedActivity])
+	}
+}
@@ -0,0 +1,170 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The classes of query failures, a failed query returns a *QueryError that
+// wraps the class, which can be checked with errors.Is.
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrAuthFailure  = errors.New("authentication failure")
+	ErrServerError  = errors.New("server error")
+)
+
+// QueryError is a classified failure of a query. Class is one of the
+// error classes above, RetryAfter is the time the server asked to wait
+// before sending the next request, if any.
+type QueryError struct {
+	Class      error
+	StatusCode int
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Class, e.Err)
+}
+
+func (e *QueryError) Unwrap() []error {
+	return []error{e.Class, e.Err}
+}
+
+// IsTransient returns whether the error is expected to go away when the
+// query is retried, which is the case for rate limits and server errors.
+func IsTransient(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrServerError)
+}
+
+// retryAfter returns the time the server asked to wait before retrying,
+// or zero if the server didn't ask for it.
+func retryAfter(err error) time.Duration {
+	var queryError *QueryError
+	if errors.As(err, &queryError) {
+		return queryError.RetryAfter
+	}
+	return 0
+}
+
+// classifyingTransport turns responses with an error status into a
+// *QueryError, since the GraphQL client only reports the status as text
+// and drops the headers.
+type classifyingTransport struct {
+	transport http.RoundTripper
+}
+
+func (t classifyingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		if req.Context().Err() != nil {
+			return nil, err
+		}
+		return nil, &QueryError{Class: ErrServerError, Err: err}
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return nil, &QueryError{
+		Class:      classifyStatus(resp),
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header, time.Now()),
+		Err:        fmt.Errorf("non-200 OK status code: %v body: %q", resp.Status, body),
+	}
+}
+
+func classifyStatus(resp *http.Response) error {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case resp.StatusCode == http.StatusForbidden:
+		// GitHub answers exceeded (secondary) rate limits with 403
+		if resp.Header.Get("Retry-After") != "" || resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			return ErrRateLimited
+		}
+		return ErrAuthFailure
+	case resp.StatusCode == http.StatusUnauthorized:
+		return ErrAuthFailure
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return ErrServerError
+	default:
+		// anything else is considered a bad request, which retrying won't fix
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+}
+
+// parseRetryAfter determines how long to wait from either the Retry-After
+// header (in seconds or as HTTP date) or the reset time of an exhausted
+// rate limit.
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	if retryAfter := header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil && date.After(now) {
+			return date.Sub(now)
+		}
+	}
+	if header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			if resetAt := time.Unix(reset, 0); resetAt.After(now) {
+				return resetAt.Sub(now)
+			}
+		}
+	}
+	return 0
+}
+
+// classifyGraphQLError classifies errors that are reported in the body of
+// a successful response, any other errors are returned as is.
+func classifyGraphQLError(err error) error {
+	var queryError *QueryError
+	if err == nil || errors.As(err, &queryError) {
+		return err
+	}
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "Could not resolve to a User"):
+		return &QueryError{Class: ErrUserNotFound, Err: err}
+	case strings.Contains(strings.ToLower(message), "rate limit"):
+		return &QueryError{Class: ErrRateLimited, Err: err}
+	}
+	return err
+}
+
+// classifyingQuerier classifies the errors of the queries it runs.
+type classifyingQuerier struct {
+	querier Querier
+}
+
+func (c classifyingQuerier) Query(ctx context.Context, q interface{}, variables map[string]interface{}) error {
+	return classifyGraphQLError(c.querier.Query(ctx, q, variables))
+}
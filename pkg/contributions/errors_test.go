@@ -0,0 +1,170 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"context"
+	"errors"
+	"github.com/avast/retry-go"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestQueryErrorClassification(t *testing.T) {
+	testCases := []struct {
+		name               string
+		scenario           string
+		userName           string
+		expectedClass      error
+		expectedTransient  bool
+		expectedRetryAfter time.Duration
+	}{
+		{
+			name:          "user not found",
+			scenario:      "user-not-found",
+			userName:      "nonexistent",
+			expectedClass: ErrUserNotFound,
+		},
+		{
+			name:              "server error",
+			scenario:          "server-error",
+			userName:          "testuser",
+			expectedClass:     ErrServerError,
+			expectedTransient: true,
+		},
+		{
+			name:               "secondary rate limit",
+			scenario:           "rate-limited",
+			userName:           "testuser",
+			expectedClass:      ErrRateLimited,
+			expectedTransient:  true,
+			expectedRetryAfter: 30 * time.Second,
+		},
+		{
+			name:          "auth failure",
+			scenario:      "auth-failure",
+			userName:      "testuser",
+			expectedClass: ErrAuthFailure,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			querier := classifyingQuerier{querier: newFixtureQuerier(t, testCase.scenario)}
+
+			_, err := queryUser(context.Background(), querier, testCase.userName)
+			if !errors.Is(err, testCase.expectedClass) {
+				t.Fatalf("expected %v, got %v", testCase.expectedClass, err)
+			}
+			if IsTransient(err) != testCase.expectedTransient {
+				t.Errorf("transient: got %t, want %t", IsTransient(err), testCase.expectedTransient)
+			}
+			if retryAfter(err) != testCase.expectedRetryAfter {
+				t.Errorf("retry after: got %v, want %v", retryAfter(err), testCase.expectedRetryAfter)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 12, 5, 16, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		header   http.Header
+		expected time.Duration
+	}{
+		{
+			name:     "no header",
+			header:   http.Header{},
+			expected: 0,
+		},
+		{
+			name:     "seconds",
+			header:   http.Header{"Retry-After": {"120"}},
+			expected: 2 * time.Minute,
+		},
+		{
+			name:     "http date",
+			header:   http.Header{"Retry-After": {now.Add(time.Minute).Format(http.TimeFormat)}},
+			expected: time.Minute,
+		},
+		{
+			name: "exhausted rate limit",
+			header: http.Header{
+				"X-Ratelimit-Remaining": {"0"},
+				"X-Ratelimit-Reset":     {"1733414700"},
+			},
+			expected: 5 * time.Minute,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			retryAfter := parseRetryAfter(testCase.header, now)
+			if retryAfter != testCase.expected {
+				t.Errorf("got %v, want %v", retryAfter, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestRetryOptions(t *testing.T) {
+	testCases := []struct {
+		name             string
+		err              error
+		expectedAttempts int
+	}{
+		{
+			name:             "transient error is retried",
+			err:              &QueryError{Class: ErrServerError, Err: errors.New("bad gateway")},
+			expectedAttempts: 3,
+		},
+		{
+			name:             "retry after is honored",
+			err:              &QueryError{Class: ErrRateLimited, RetryAfter: time.Millisecond, Err: errors.New("slow down")},
+			expectedAttempts: 3,
+		},
+		{
+			name:             "user not found is not retried",
+			err:              &QueryError{Class: ErrUserNotFound, Err: errors.New("no such user")},
+			expectedAttempts: 1,
+		},
+		{
+			name:             "unclassified error is not retried",
+			err:              errors.New("Field 'unknown' doesn't exist on type 'Query'"),
+			expectedAttempts: 1,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			opts := ContributionReportGeneratorOptions{RetryAttempts: 3, RetryDelay: time.Millisecond}
+			attempts := 0
+			err := retry.Do(func() error {
+				attempts++
+				return testCase.err
+			}, opts.retryOptions(context.Background())...)
+			if !errors.Is(err, testCase.err) {
+				t.Errorf("expected %v, got %v", testCase.err, err)
+			}
+			if attempts != testCase.expectedAttempts {
+				t.Errorf("attempts: got %d, want %d", attempts, testCase.expectedAttempts)
+			}
+		})
+	}
+}
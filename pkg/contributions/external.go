@@ -0,0 +1,73 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+package contributions
+
+// ExternalContributions holds the contributions of a user outside of
+// GitHub, and those made under other GitHub handles of the same person.
+// They are not scoped to a repository or organization, thus they are only
+// added to the report that is returned to the caller, never to the reports
+// it aggregates.
+type ExternalContributions struct {
+	MailingList *MailingListActivity `yaml:"mailingList,omitempty"`
+	Slack       *SlackActivity       `yaml:"slack,omitempty"`
+	OtherLogins []LoginActivity      `yaml:"otherLogins,omitempty"`
+}
+
+// externalContributions gives the generator access to the external
+// contributions of the report types that embed them.
+func (e *ExternalContributions) externalContributions() *ExternalContributions {
+	return e
+}
+
+func (e *ExternalContributions) hasContributions() bool {
+	for _, otherLogin := range e.OtherLogins {
+		if otherLogin.ActivityCounts.Total() > 0 {
+			return true
+		}
+	}
+	return (e.MailingList != nil && e.MailingList.MailsSent > 0) ||
+		(e.Slack != nil && e.Slack.messages() > 0)
+}
+
+func (e *ExternalContributions) activityCounts() ActivityCounts {
+	activityCounts := ActivityCounts{}
+	if e.MailingList != nil {
+		activityCounts[MailsSentActivity] = e.MailingList.MailsSent
+		activityCounts[MailThreadsStartedActivity] = e.MailingList.ThreadsStarted
+	}
+	if e.Slack != nil {
+		activityCounts[SlackMessagesActivity] = e.Slack.messages()
+		activityCounts[SlackThreadsAnsweredActivity] = e.Slack.threadsAnswered()
+	}
+	for _, otherLogin := range e.OtherLogins {
+		activityCounts.Add(otherLogin.ActivityCounts)
+	}
+	return activityCounts
+}
+
+func (e *ExternalContributions) summary() string {
+	var summary string
+	if e.MailingList != nil {
+		summary += e.MailingList.summary()
+	}
+	if e.Slack != nil {
+		summary += e.Slack.summary()
+	}
+	return summary + otherLoginsSummary(e.OtherLogins)
+}
@@ -0,0 +1,119 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fixture is a recorded response of the GitHub GraphQL API. It is replayed
+// for a request if the query of the request contains Query and each of the
+// Variables matches the request variable of the same name. String variables
+// match if the request variable contains the fixture value, a null fixture
+// value only matches a null or missing request variable. Status and Headers
+// are set on the response if given.
+type fixture struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+	Status    int                    `json:"status"`
+	Headers   map[string]string      `json:"headers"`
+	Response  json.RawMessage        `json:"response"`
+}
+
+func (f fixture) matches(query string, variables map[string]interface{}) bool {
+	if !strings.Contains(query, f.Query) {
+		return false
+	}
+	for name, value := range f.Variables {
+		requestValue := variables[name]
+		switch v := value.(type) {
+		case nil:
+			if requestValue != nil {
+				return false
+			}
+		case string:
+			requestString, ok := requestValue.(string)
+			if !ok || !strings.Contains(requestString, v) {
+				return false
+			}
+		default:
+			if fmt.Sprint(v) != fmt.Sprint(requestValue) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// newFixtureQuerier starts a GraphQL stand-in that replays the fixtures
+// from testdata/<scenario>.json and returns a Querier that targets it.
+// Requests that no fixture matches fail the test.
+func newFixtureQuerier(t *testing.T, scenario string) Querier {
+	t.Helper()
+	fixtureBytes, err := os.ReadFile(filepath.Join("testdata", scenario+".json"))
+	if err != nil {
+		t.Fatalf("failed to read fixtures: %v", err)
+	}
+	var fixtures []fixture
+	err = json.Unmarshal(fixtureBytes, &fixtures)
+	if err != nil {
+		t.Fatalf("failed to parse fixtures: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, f := range fixtures {
+			if !f.matches(request.Query, request.Variables) {
+				continue
+			}
+			status := f.Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			for name, value := range f.Headers {
+				w.Header().Set(name, value)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = w.Write(f.Response)
+			return
+		}
+		t.Errorf("no fixture for query %s with variables %v", request.Query, request.Variables)
+		http.Error(w, "no fixture", http.StatusNotImplemented)
+	}))
+	t.Cleanup(server.Close)
+
+	return newGithubClient(server.URL, server.Client())
+}
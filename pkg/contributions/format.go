@@ -0,0 +1,227 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is an output format of contribution reports.
+type Format string
+
+const (
+	// YAMLFormat contains the complete query results of the report.
+	YAMLFormat Format = "yaml"
+	// JSONFormat contains the same data as YAMLFormat, for scripts.
+	JSONFormat Format = "json"
+	// CSVFormat contains a header and one row with the activity counts of
+	// the user, so that the rows of several users can be combined into a
+	// spreadsheet.
+	CSVFormat Format = "csv"
+	// MarkdownFormat contains a table of the activity counts, to be pasted
+	// into a GitHub issue.
+	MarkdownFormat Format = "markdown"
+)
+
+var Formats = []Format{
+	YAMLFormat,
+	JSONFormat,
+	CSVFormat,
+	MarkdownFormat,
+}
+
+// ParseFormat returns the format with the given name.
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q (supported: %v)", name, Formats)
+}
+
+// Extension returns the file extension for the format.
+func (f Format) Extension() string {
+	if f == MarkdownFormat {
+		return "md"
+	}
+	return string(f)
+}
+
+// Formatter writes a contribution report in an output format.
+type Formatter interface {
+	Format(w io.Writer, report ContributionReport) error
+}
+
+// NewFormatter returns the formatter for the format.
+func NewFormatter(format Format) (Formatter, error) {
+	switch format {
+	case YAMLFormat:
+		return yamlFormatter{}, nil
+	case JSONFormat:
+		return jsonFormatter{}, nil
+	case CSVFormat:
+		return csvFormatter{}, nil
+	case MarkdownFormat:
+		return markdownFormatter{}, nil
+	}
+	return nil, fmt.Errorf("unknown format %q (supported: %v)", format, Formats)
+}
+
+type yamlFormatter struct{}
+
+func (yamlFormatter) Format(w io.Writer, report ContributionReport) error {
+	return yaml.NewEncoder(w).Encode(report)
+}
+
+type jsonFormatter struct{}
+
+func (jsonFormatter) Format(w io.Writer, report ContributionReport) error {
+	return WriteJSON(w, report)
+}
+
+// WriteJSON writes the value as indented JSON, where the keys are the same
+// as when the value is written as YAML.
+func WriteJSON(w io.Writer, v interface{}) error {
+	// the report types only carry yaml tags, thus the value is converted
+	// into generic maps and lists first
+	buf, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var generic interface{}
+	err = yaml.Unmarshal(buf, &generic)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(generic)
+}
+
+type csvFormatter struct{}
+
+func (csvFormatter) Format(w io.Writer, report ContributionReport) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write(CSVHeader())
+	if err != nil {
+		return err
+	}
+	err = writer.Write(CSVRecord(report))
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// CSVHeader returns the column names of the CSV records of reports.
+func CSVHeader() []string {
+	header := []string{"user", "scope", "since", "until"}
+	for _, activityType := range ActivityTypes {
+		header = append(header, string(activityType))
+	}
+	return append(header, "total")
+}
+
+// CSVRecord returns the CSV record for the report, matching CSVHeader.
+func CSVRecord(report ContributionReport) []string {
+	scope, window := describeReport(report)
+	activityCounts := report.ActivityCounts()
+	record := []string{
+		report.Identity().Login,
+		scope,
+		window.Since.Format(time.DateOnly),
+		window.lastDay().Format(time.DateOnly),
+	}
+	for _, activityType := range ActivityTypes {
+		record = append(record, strconv.Itoa(activityCounts[activityType]))
+	}
+	return append(record, strconv.Itoa(activityCounts.Total()))
+}
+
+type markdownFormatter struct{}
+
+func (markdownFormatter) Format(w io.Writer, report ContributionReport) error {
+	scope, window := describeReport(report)
+	columns := []string{"total"}
+	columnCounts := []ActivityCounts{report.ActivityCounts()}
+	if scopesReport, ok := report.(*UserContributionReportForScopes); ok {
+		for _, scopeReport := range scopesReport.reports() {
+			columns = append(columns, scopeReport.scope.String())
+			columnCounts = append(columnCounts, scopeReport.ActivityCounts())
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "## Activity of @%s in %s\n\n", report.Identity().Login, scope)
+	fmt.Fprintf(&out, "%s to %s\n\n", window.Since.Format(time.DateOnly), window.lastDay().Format(time.DateOnly))
+	fmt.Fprintf(&out, "| activity | %s |\n", strings.Join(columns, " | "))
+	fmt.Fprintf(&out, "|---%s|\n", strings.Repeat("|--:", len(columns)))
+	for _, activityType := range ActivityTypes {
+		fmt.Fprintf(&out, "| %s |", activityType)
+		for _, counts := range columnCounts {
+			fmt.Fprintf(&out, " %d |", counts[activityType])
+		}
+		out.WriteString("\n")
+	}
+	out.WriteString("| **total** |")
+	for _, counts := range columnCounts {
+		fmt.Fprintf(&out, " **%d** |", counts.Total())
+	}
+	out.WriteString("\n")
+	if lastActivity := reportTimeline(report).LastActivity; lastActivity != nil {
+		fmt.Fprintf(&out, "\nlast activity: %s\n", lastActivity.Format(time.DateOnly))
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// describeReport returns the scope and the window that the report covers.
+func describeReport(report ContributionReport) (string, ReportWindow) {
+	switch r := report.(type) {
+	case *UserContributionReportForRepository:
+		return Scope{Org: r.Org, Repo: r.Repo}.String(), r.Window
+	case *UserContributionReportForOrganization:
+		return Scope{Org: r.Org}.String(), r.Window
+	case *UserContributionReportForScopes:
+		return r.Name, r.Window
+	}
+	return "", ReportWindow{}
+}
+
+func reportTimeline(report ContributionReport) *Timeline {
+	switch r := report.(type) {
+	case *UserContributionReportForRepository:
+		return r.timeline()
+	case *UserContributionReportForOrganization:
+		return r.timeline()
+	case *UserContributionReportForScopes:
+		return r.timeline()
+	}
+	return &Timeline{}
+}
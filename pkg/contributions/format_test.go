@@ -0,0 +1,158 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newFormatTestReport() *UserContributionReportForRepository {
+	report := &UserContributionReportForRepository{
+		Org:      "kubevirt",
+		Repo:     "community",
+		UserName: "testuser",
+		UserID:   "U_testuser",
+		Window: ReportWindow{
+			Since: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Until: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	report.IssuesCreated.IssueCount = 2
+	report.PullRequestsReviewed.IssueCount = 3
+	report.CommitsByUser.DefaultBranchRef.Target.Fragment.History.TotalCount = 4
+	return report
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, format := range Formats {
+		parsed, err := ParseFormat(string(format))
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", format, err)
+		}
+		if parsed != format {
+			t.Errorf("got %q, want %q", parsed, format)
+		}
+	}
+	_, err := ParseFormat("xml")
+	if err == nil {
+		t.Errorf("expected error for unknown format")
+	}
+}
+
+func TestFormatters(t *testing.T) {
+	testCases := []struct {
+		format   Format
+		expected []string
+	}{
+		{
+			format:   YAMLFormat,
+			expected: []string{"issuesCreated:", "username: testuser"},
+		},
+		{
+			format:   CSVFormat,
+			expected: []string{"user,scope,since,until,issuesCreated,", "testuser,kubevirt/community,2024-01-01,2024-06-30,2,0,0,3,0,4,0,0,0,0,0,0,0,0,0,0,0,0,9"},
+		},
+		{
+			format:   MarkdownFormat,
+			expected: []string{"## Activity of @testuser in kubevirt/community", "2024-01-01 to 2024-06-30", "| issuesCreated | 2 |", "| **total** | **9** |"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(string(testCase.format), func(t *testing.T) {
+			formatter, err := NewFormatter(testCase.format)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var out bytes.Buffer
+			err = formatter.Format(&out, newFormatTestReport())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, expected := range testCase.expected {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("expected %q in output:\n%s", expected, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestJSONFormatterUsesYAMLKeys(t *testing.T) {
+	formatter, err := NewFormatter(JSONFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out bytes.Buffer
+	err = formatter.Format(&out, newFormatTestReport())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded map[string]interface{}
+	err = json.Unmarshal(out.Bytes(), &decoded)
+	if err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	issuesCreated, ok := decoded["issuesCreated"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected issuesCreated object, got %v", decoded["issuesCreated"])
+	}
+	if issuesCreated["issueCount"] != float64(2) {
+		t.Errorf("issue count: got %v, want 2", issuesCreated["issueCount"])
+	}
+}
+
+func TestWriteToFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "reports")
+	for _, format := range Formats {
+		t.Run(string(format), func(t *testing.T) {
+			var paths []string
+			// writing the same report twice replaces the file
+			for i := 0; i < 2; i++ {
+				path, err := newFormatTestReport().WriteToFile(dir, "testuser", format)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				paths = append(paths, path)
+			}
+			expectedPath := filepath.Join(dir, "user-activity-testuser-kubevirt_community-2024-01-01_2024-06-30."+format.Extension())
+			for _, path := range paths {
+				if path != expectedPath {
+					t.Errorf("path: got %q, want %q", path, expectedPath)
+				}
+			}
+			if _, err := os.Stat(expectedPath); err != nil {
+				t.Errorf("expected file: %v", err)
+			}
+		})
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != len(Formats) {
+		t.Errorf("expected %d files, got %d", len(Formats), len(entries))
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"strings"
+)
+
+// Identity maps a person to all GitHub handles they contribute with, and to
+// the mail addresses they author commits with that are not linked to any of
+// these accounts.
+type Identity struct {
+	Name   string   `yaml:"name"`
+	Github []string `yaml:"github"`
+	Emails []string `yaml:"emails,omitempty"`
+}
+
+// Identities holds the identities of people that contribute with more than
+// one GitHub handle or with unlinked commit emails.
+type Identities struct {
+	Identities []Identity `yaml:"identities"`
+}
+
+// ReadIdentities reads the identities from a yaml file, where every identity
+// needs at least one GitHub handle and a handle must not belong to more
+// than one identity.
+func ReadIdentities(path string) (*Identities, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read identities: %v", err)
+	}
+	identities := &Identities{}
+	err = yaml.Unmarshal(buf, identities)
+	if err != nil {
+		return nil, fmt.Errorf("in file %q: %v", path, err)
+	}
+	err = identities.validate()
+	if err != nil {
+		return nil, fmt.Errorf("in file %q: %v", path, err)
+	}
+	return identities, nil
+}
+
+func (i *Identities) validate() error {
+	identityByHandle := make(map[string]string)
+	for index, identity := range i.Identities {
+		if len(identity.Github) == 0 {
+			return fmt.Errorf("identity %d (%s) has no github handle", index, identity.Name)
+		}
+		for _, handle := range identity.Github {
+			key := strings.ToLower(handle)
+			if name, exists := identityByHandle[key]; exists {
+				return fmt.Errorf("github handle %s is used by identities %q and %q", handle, name, identity.Name)
+			}
+			identityByHandle[key] = identity.Name
+		}
+	}
+	return nil
+}
+
+// Lookup returns the identity that the GitHub handle belongs to.
+func (i *Identities) Lookup(userName string) (Identity, bool) {
+	if i == nil {
+		return Identity{}, false
+	}
+	for _, identity := range i.Identities {
+		if containsFold(identity.Github, userName) {
+			return identity, true
+		}
+	}
+	return Identity{}, false
+}
+
+// logins returns all GitHub handles of the person the handle belongs to,
+// starting with the handle itself.
+func (i *Identities) logins(userName string) []string {
+	logins := []string{userName}
+	identity, exists := i.Lookup(userName)
+	if !exists {
+		return logins
+	}
+	for _, handle := range identity.Github {
+		if !strings.EqualFold(handle, userName) {
+			logins = append(logins, handle)
+		}
+	}
+	return logins
+}
+
+// emails returns the commit emails of the person the handle belongs to.
+func (i *Identities) emails(userName string) []string {
+	identity, _ := i.Lookup(userName)
+	return identity.Emails
+}
+
+// LoginActivity holds the activity counts of a user under another GitHub
+// handle of the same person.
+type LoginActivity struct {
+	Login          string         `yaml:"login"`
+	ActivityCounts ActivityCounts `yaml:"activityCounts"`
+}
+
+func otherLoginsSummary(otherLogins []LoginActivity) string {
+	if len(otherLogins) == 0 {
+		return ""
+	}
+	summary := "    other logins:\n"
+	for _, otherLogin := range otherLogins {
+		summary += fmt.Sprintf("        %-16s %d\n", otherLogin.Login+":", otherLogin.ActivityCounts.Total())
+	}
+	return summary
+}
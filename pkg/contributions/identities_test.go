@@ -0,0 +1,192 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadIdentities(t *testing.T) {
+	testCases := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{
+			name: "valid",
+			content: `identities:
+- name: Jane Doe
+  github: [jdoe, jane-corp]
+  emails: [jane@corp.example]
+- name: John Doe
+  github: [johnd]
+`,
+		},
+		{
+			name: "identity without handle",
+			content: `identities:
+- name: Jane Doe
+  emails: [jane@corp.example]
+`,
+			expectedError: "has no github handle",
+		},
+		{
+			name: "handle in two identities",
+			content: `identities:
+- name: Jane Doe
+  github: [jdoe]
+- name: John Doe
+  github: [JDoe]
+`,
+			expectedError: "is used by identities",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "identities.yaml")
+			err := os.WriteFile(path, []byte(testCase.content), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = ReadIdentities(path)
+			if testCase.expectedError == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if testCase.expectedError != "" && (err == nil || !strings.Contains(err.Error(), testCase.expectedError)) {
+				t.Errorf("expected error containing %q, got %v", testCase.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestIdentitiesLogins(t *testing.T) {
+	identities := &Identities{Identities: []Identity{
+		{Name: "Jane Doe", Github: []string{"jdoe", "jane-corp"}, Emails: []string{"jane@corp.example"}},
+	}}
+	testCases := []struct {
+		name           string
+		identities     *Identities
+		userName       string
+		expectedLogins []string
+		expectedEmails []string
+	}{
+		{
+			name:           "primary handle",
+			identities:     identities,
+			userName:       "jdoe",
+			expectedLogins: []string{"jdoe", "jane-corp"},
+			expectedEmails: []string{"jane@corp.example"},
+		},
+		{
+			name:           "other handle in different case",
+			identities:     identities,
+			userName:       "Jane-Corp",
+			expectedLogins: []string{"Jane-Corp", "jdoe"},
+			expectedEmails: []string{"jane@corp.example"},
+		},
+		{
+			name:           "unmapped handle",
+			identities:     identities,
+			userName:       "someone",
+			expectedLogins: []string{"someone"},
+		},
+		{
+			name:           "no identities",
+			userName:       "jdoe",
+			expectedLogins: []string{"jdoe"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			logins := testCase.identities.logins(testCase.userName)
+			if !reflect.DeepEqual(logins, testCase.expectedLogins) {
+				t.Errorf("logins: got %v, want %v", logins, testCase.expectedLogins)
+			}
+			emails := testCase.identities.emails(testCase.userName)
+			if !reflect.DeepEqual(emails, testCase.expectedEmails) {
+				t.Errorf("emails: got %v, want %v", emails, testCase.expectedEmails)
+			}
+		})
+	}
+}
+
+func TestGenerateReportWithIdentities(t *testing.T) {
+	identities, err := ReadIdentities(filepath.Join("testdata", "identities.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	generator := newTestGenerator(t, "identities", ContributionReportGeneratorOptions{
+		Org:        "kubevirt",
+		Repo:       "community",
+		Since:      time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+		Until:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Identities: identities,
+	})
+
+	report, err := generator.GenerateReport(context.Background(), "testuser")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repoReport := report.(*UserContributionReportForRepository)
+
+	history := repoReport.CommitsByUser.DefaultBranchRef.Target.Fragment.History
+	var commits []string
+	for _, commit := range history.Nodes {
+		commits = append(commits, commit.CommitUrl)
+	}
+	expectedCommits := []string{
+		"https://github.com/kubevirt/community/commit/1",
+		"https://github.com/kubevirt/community/commit/3",
+	}
+	if !reflect.DeepEqual(commits, expectedCommits) || history.TotalCount != 2 {
+		t.Errorf("commits: got %v (total %d), want %v", commits, history.TotalCount, expectedCommits)
+	}
+
+	expectedOtherLogins := []LoginActivity{
+		{Login: "testuser-work", ActivityCounts: ActivityCounts{PullRequestsCreatedActivity: 1, CommitsActivity: 1}},
+	}
+	var otherLogins []LoginActivity
+	for _, otherLogin := range repoReport.OtherLogins {
+		activityCounts := ActivityCounts{}
+		for activityType, count := range otherLogin.ActivityCounts {
+			if count > 0 {
+				activityCounts[activityType] = count
+			}
+		}
+		otherLogins = append(otherLogins, LoginActivity{Login: otherLogin.Login, ActivityCounts: activityCounts})
+	}
+	if !reflect.DeepEqual(otherLogins, expectedOtherLogins) {
+		t.Errorf("other logins: got %v, want %v", otherLogins, expectedOtherLogins)
+	}
+
+	activityCounts := report.ActivityCounts()
+	if activityCounts[CommitsActivity] != 3 || activityCounts[PullRequestsCreatedActivity] != 1 {
+		t.Errorf("activity counts: got %v, want 3 commits and 1 pull request", activityCounts)
+	}
+	if !strings.Contains(report.Summary(), "testuser-work:") {
+		t.Errorf("summary does not contain other login: %s", report.Summary())
+	}
+}
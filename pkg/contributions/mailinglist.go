@@ -0,0 +1,202 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+package contributions
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"mime"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Mail is a mail of a mailing list archive, where only the headers that
+// are needed to attribute the mail to a user are kept.
+type Mail struct {
+	MessageID  string    `yaml:"messageId"`
+	From       string    `yaml:"from"`
+	Subject    string    `yaml:"subject"`
+	Date       time.Time `yaml:"date"`
+	InReplyTo  string    `yaml:"inReplyTo,omitempty"`
+	References string    `yaml:"references,omitempty"`
+}
+
+// startsThread returns whether the mail is not a reply to another mail.
+func (m Mail) startsThread() bool {
+	return m.InReplyTo == "" && m.References == ""
+}
+
+// MailingListArchive holds the mails of a mailing list, i.e. read from an
+// mbox export of kubevirt-dev.
+type MailingListArchive struct {
+	Name  string
+	Mails []Mail
+}
+
+// ReadMailingListArchive reads the mbox file at path, where the name of the
+// mailing list is taken from the file name.
+func ReadMailingListArchive(path string) (*MailingListArchive, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mbox file: %v", err)
+	}
+	defer file.Close()
+	mails, err := ParseMbox(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse mbox file %q: %v", path, err)
+	}
+	return &MailingListArchive{
+		Name:  strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Mails: mails,
+	}, nil
+}
+
+// ParseMbox parses the mails of an mbox file, where each mail starts with a
+// `From ` line. Mails whose headers can't be parsed are skipped.
+func ParseMbox(r io.Reader) ([]Mail, error) {
+	var mails []Mail
+	var message bytes.Buffer
+	flush := func() {
+		if message.Len() == 0 {
+			return
+		}
+		mail, err := parseMail(message.Bytes())
+		if err == nil {
+			mails = append(mails, mail)
+		}
+		message.Reset()
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	previousLineEmpty := true
+	for scanner.Scan() {
+		line := scanner.Text()
+		if previousLineEmpty && strings.HasPrefix(line, "From ") {
+			flush()
+			previousLineEmpty = false
+			continue
+		}
+		message.WriteString(line)
+		message.WriteString("\n")
+		previousLineEmpty = strings.TrimSpace(line) == ""
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return mails, nil
+}
+
+var headerDecoder = new(mime.WordDecoder)
+
+func parseMail(message []byte) (Mail, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(message))
+	if err != nil {
+		return Mail{}, err
+	}
+	from, err := mail.ParseAddress(msg.Header.Get("From"))
+	if err != nil {
+		return Mail{}, fmt.Errorf("invalid sender: %v", err)
+	}
+	subject, err := headerDecoder.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+	date, err := msg.Header.Date()
+	if err != nil {
+		return Mail{}, fmt.Errorf("invalid date: %v", err)
+	}
+	return Mail{
+		MessageID:  strings.TrimSpace(msg.Header.Get("Message-ID")),
+		From:       strings.ToLower(from.Address),
+		Subject:    subject,
+		Date:       date,
+		InReplyTo:  strings.TrimSpace(msg.Header.Get("In-Reply-To")),
+		References: strings.TrimSpace(msg.Header.Get("References")),
+	}, nil
+}
+
+// EmailMapping maps the mail addresses of users to their GitHub handles,
+// where addresses are kept in lower case.
+type EmailMapping map[string]string
+
+// ReadEmailMapping reads the mapping from a yaml file that maps mail
+// addresses to GitHub handles.
+func ReadEmailMapping(path string) (EmailMapping, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read email mapping: %v", err)
+	}
+	var entries map[string]string
+	err = yaml.Unmarshal(buf, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("in file %q: %v", path, err)
+	}
+	mapping := make(EmailMapping, len(entries))
+	for address, userName := range entries {
+		mapping[strings.ToLower(strings.TrimSpace(address))] = userName
+	}
+	return mapping, nil
+}
+
+// userName returns the GitHub handle that the address is mapped to.
+func (m EmailMapping) userName(address string) (string, bool) {
+	userName, exists := m[strings.ToLower(address)]
+	return userName, exists
+}
+
+// MailingListActivity holds the mails that a user sent to a mailing list
+// within the report window.
+type MailingListActivity struct {
+	List           string `yaml:"list"`
+	MailsSent      int    `yaml:"mailsSent"`
+	ThreadsStarted int    `yaml:"threadsStarted"`
+	Mails          []Mail `yaml:"mails,omitempty"`
+}
+
+// activity returns the mails that have been sent from an address that is
+// mapped to one of the handles of the user within the window.
+func (a *MailingListArchive) activity(userNames []string, mapping EmailMapping, window ReportWindow) *MailingListActivity {
+	activity := &MailingListActivity{List: a.Name}
+	for _, mail := range a.Mails {
+		sender, mapped := mapping.userName(mail.From)
+		if !mapped || !containsFold(userNames, sender) || !window.contains(mail.Date) {
+			continue
+		}
+		activity.Mails = append(activity.Mails, mail)
+		activity.MailsSent++
+		if mail.startsThread() {
+			activity.ThreadsStarted++
+		}
+	}
+	return activity
+}
+
+func (a *MailingListActivity) summary() string {
+	return fmt.Sprintf(`    mailing list %s:
+        mails sent:      %d
+        threads started: %d
+`, a.List, a.MailsSent, a.ThreadsStarted)
+}
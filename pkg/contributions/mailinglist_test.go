@@ -0,0 +1,169 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+package contributions
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadMailingListArchive(t *testing.T) {
+	archive, err := ReadMailingListArchive("testdata/kubevirt-dev.mbox")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if archive.Name != "kubevirt-dev" {
+		t.Errorf("name: got %q, want %q", archive.Name, "kubevirt-dev")
+	}
+	expectedMails := []Mail{
+		{
+			MessageID: "<proposal-1@example.com>",
+			From:      "test.user@example.com",
+			Subject:   "[kubevirt-dev] Proposal: live migration policies",
+			Date:      time.Date(2024, 11, 5, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			MessageID:  "<reply-1@example.com>",
+			From:       "other@example.com",
+			Subject:    "Re: [kubevirt-dev] Proposal: live migration policies",
+			Date:       time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC),
+			InReplyTo:  "<proposal-1@example.com>",
+			References: "<proposal-1@example.com>",
+		},
+		{
+			MessageID:  "<reply-2@work.example.com>",
+			From:       "testuser@work.example.com",
+			Subject:    "Re: [kubevirt-dev] Proposal: live migration policies",
+			Date:       time.Date(2024, 11, 6, 8, 0, 0, 0, time.UTC),
+			InReplyTo:  "<reply-1@example.com>",
+			References: "<proposal-1@example.com> <reply-1@example.com>",
+		},
+		{
+			MessageID: "<old-1@example.com>",
+			From:      "test.user@example.com",
+			Subject:   "[kubevirt-dev] Before the window",
+			Date:      time.Date(2024, 9, 30, 23, 0, 0, 0, time.UTC),
+		},
+	}
+	if len(archive.Mails) != len(expectedMails) {
+		t.Fatalf("mails: got %d, want %d", len(archive.Mails), len(expectedMails))
+	}
+	for i, mail := range archive.Mails {
+		if !mail.Date.Equal(expectedMails[i].Date) {
+			t.Errorf("mail %d date: got %v, want %v", i, mail.Date, expectedMails[i].Date)
+		}
+		mail.Date = expectedMails[i].Date
+		if !reflect.DeepEqual(mail, expectedMails[i]) {
+			t.Errorf("mail %d: got %+v, want %+v", i, mail, expectedMails[i])
+		}
+	}
+}
+
+func TestMailingListActivity(t *testing.T) {
+	archive, err := ReadMailingListArchive("testdata/kubevirt-dev.mbox")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mapping, err := ReadEmailMapping("testdata/email-mapping.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	window := ReportWindow{
+		Since: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	testCases := []struct {
+		name                   string
+		userName               string
+		expectedMailsSent      int
+		expectedThreadsStarted int
+	}{
+		{
+			name:                   "mails from several addresses",
+			userName:               "testuser",
+			expectedMailsSent:      2,
+			expectedThreadsStarted: 1,
+		},
+		{
+			name:                   "replies only",
+			userName:               "otheruser",
+			expectedMailsSent:      1,
+			expectedThreadsStarted: 0,
+		},
+		{
+			name:                   "unmapped user",
+			userName:               "unknown",
+			expectedMailsSent:      0,
+			expectedThreadsStarted: 0,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			activity := archive.activity([]string{testCase.userName}, mapping, window)
+			if activity.MailsSent != testCase.expectedMailsSent || len(activity.Mails) != testCase.expectedMailsSent {
+				t.Errorf("mails sent: got %d, want %d", activity.MailsSent, testCase.expectedMailsSent)
+			}
+			if activity.ThreadsStarted != testCase.expectedThreadsStarted {
+				t.Errorf("threads started: got %d, want %d", activity.ThreadsStarted, testCase.expectedThreadsStarted)
+			}
+		})
+	}
+}
+
+func TestGenerateReportWithMailingList(t *testing.T) {
+	archive, err := ReadMailingListArchive("testdata/kubevirt-dev.mbox")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mapping, err := ReadEmailMapping("testdata/email-mapping.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	generator := newTestGenerator(t, "sig", ContributionReportGeneratorOptions{
+		Scopes:       []Scope{{Org: "kubevirt", Repo: "community"}, {Org: "kubevirt", Repo: "kubevirt"}},
+		ScopesName:   "sig-test",
+		Since:        time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		Until:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		MailingList:  archive,
+		EmailMapping: mapping,
+	})
+
+	report, err := generator.GenerateReport(context.Background(), "testuser")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	activityCounts := report.ActivityCounts()
+	if activityCounts[MailsSentActivity] != 2 {
+		t.Errorf("mails sent: got %d, want %d", activityCounts[MailsSentActivity], 2)
+	}
+	if activityCounts[MailThreadsStartedActivity] != 1 {
+		t.Errorf("threads started: got %d, want %d", activityCounts[MailThreadsStartedActivity], 1)
+	}
+	for _, scopeReport := range report.(*UserContributionReportForScopes).reports() {
+		if scopeReport.ActivityCounts()[MailsSentActivity] != 0 {
+			t.Errorf("mails counted for scope %s", scopeReport.scope)
+		}
+	}
+	if !strings.Contains(report.Summary(), "mailing list kubevirt-dev:") {
+		t.Errorf("summary does not contain mailing list: %s", report.Summary())
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PromotionBar is the review and authorship activity within the report
+// window that a user needs to show to be nominated for a role, on top of
+// reaching the score threshold of the role. ApprovedMerged is the number of
+// merged pull requests that the user approved, ReviewedOrAuthored the
+// number of distinct pull requests that the user reviewed or authored.
+type PromotionBar struct {
+	ApprovedMerged     int `yaml:"approvedMerged"`
+	ReviewedOrAuthored int `yaml:"reviewedOrAuthored"`
+}
+
+// nextRoles maps the roles that are listed in OWNERS files (or org
+// membership) to the role a user in that role can be nominated for.
+var nextRoles = map[Role]Role{
+	OrgMemberRole: ReviewerRole,
+	ReviewerRole:  ApproverRole,
+}
+
+// NextRole returns the role that a user in the role can be nominated for.
+func NextRole(role Role) (Role, bool) {
+	nextRole, exists := nextRoles[role]
+	return nextRole, exists
+}
+
+// PromotionEvidence holds the URLs of the pull requests that back a
+// nomination, as they are linked in the sponsorship pull request.
+type PromotionEvidence struct {
+	Reviewed       []string `yaml:"reviewed,omitempty"`
+	ApprovedMerged []string `yaml:"approvedMerged,omitempty"`
+	Authored       []string `yaml:"authored,omitempty"`
+}
+
+// NewPromotionEvidence collects the pull requests that the user of the
+// report reviewed, approved (if merged) and authored within the report
+// window.
+func NewPromotionEvidence(report ContributionReport) *PromotionEvidence {
+	evidence := &PromotionEvidence{}
+	switch r := report.(type) {
+	case *UserContributionReportForRepository:
+		evidence.add(&r.UserContributions, r.Window)
+	case *UserContributionReportForOrganization:
+		evidence.add(&r.UserContributions, r.Window)
+	case *UserContributionReportForScopes:
+		for _, scopeReport := range r.reports() {
+			evidence.merge(NewPromotionEvidence(scopeReport.ContributionReport))
+		}
+	}
+	sort.Strings(evidence.Reviewed)
+	sort.Strings(evidence.ApprovedMerged)
+	sort.Strings(evidence.Authored)
+	return evidence
+}
+
+func (e *PromotionEvidence) add(u *UserContributions, window ReportWindow) {
+	for _, node := range u.PullRequestsReviewed.Nodes {
+		pullRequest := node.PullRequestReview
+		for _, review := range pullRequest.Reviews.Nodes {
+			if window.contains(review.SubmittedAt) {
+				e.Reviewed = append(e.Reviewed, pullRequest.URL)
+				break
+			}
+		}
+	}
+	e.ApprovedMerged = append(e.ApprovedMerged, u.reviewDetails(window).ApprovedMerged...)
+	for _, node := range u.PullRequestsCreated.Nodes {
+		if window.contains(node.PullRequest.CreatedAt) {
+			e.Authored = append(e.Authored, node.PullRequest.URL)
+		}
+	}
+}
+
+func (e *PromotionEvidence) merge(other *PromotionEvidence) {
+	e.Reviewed = append(e.Reviewed, other.Reviewed...)
+	e.ApprovedMerged = append(e.ApprovedMerged, other.ApprovedMerged...)
+	e.Authored = append(e.Authored, other.Authored...)
+}
+
+// reviewedOrAuthored returns the number of distinct pull requests that the
+// user reviewed or authored.
+func (e *PromotionEvidence) reviewedOrAuthored() int {
+	pullRequests := make(map[string]struct{})
+	for _, urls := range [][]string{e.Reviewed, e.Authored} {
+		for _, url := range urls {
+			pullRequests[url] = struct{}{}
+		}
+	}
+	return len(pullRequests)
+}
+
+// PromotionBar returns the bar that a user needs to meet to be nominated
+// for the role. If no bar is configured for the role, nobody meets it.
+func (c ScoringConfig) PromotionBar(role Role) (PromotionBar, bool) {
+	bar, exists := c.PromotionBars[role]
+	return bar, exists
+}
+
+// MeetsPromotionBar returns whether the activity of the user reaches the
+// score threshold and the promotion bar of the role. If not, the reasons
+// name the criteria that are not met.
+func (c ScoringConfig) MeetsPromotionBar(role Role, activityCounts ActivityCounts, evidence *PromotionEvidence) (bool, []string) {
+	var reasons []string
+	if score, threshold := c.Score(activityCounts), c.Threshold(role); score < threshold {
+		reasons = append(reasons, fmt.Sprintf("score %.1f below threshold %.1f", score, threshold))
+	}
+	bar, exists := c.PromotionBar(role)
+	if !exists {
+		return false, append(reasons, fmt.Sprintf("no promotion bar for role %s", role))
+	}
+	if approvedMerged := len(evidence.ApprovedMerged); approvedMerged < bar.ApprovedMerged {
+		reasons = append(reasons, fmt.Sprintf("approved %d merged pull requests, %d required", approvedMerged, bar.ApprovedMerged))
+	}
+	if reviewedOrAuthored := evidence.reviewedOrAuthored(); reviewedOrAuthored < bar.ReviewedOrAuthored {
+		reasons = append(reasons, fmt.Sprintf("reviewed or authored %d pull requests, %d required", reviewedOrAuthored, bar.ReviewedOrAuthored))
+	}
+	return len(reasons) == 0, reasons
+}
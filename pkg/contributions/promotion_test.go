@@ -0,0 +1,149 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewPromotionEvidence(t *testing.T) {
+	generator := newTestGenerator(t, "repository", ContributionReportGeneratorOptions{
+		Org:   "kubevirt",
+		Repo:  "community",
+		Since: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	report, err := generator.GenerateReport(context.Background(), "testuser")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	evidence := NewPromotionEvidence(report)
+
+	expectedEvidence := &PromotionEvidence{
+		Reviewed:       []string{"https://github.com/kubevirt/community/pull/5"},
+		ApprovedMerged: []string{"https://github.com/kubevirt/community/pull/5"},
+		Authored:       []string{"https://github.com/kubevirt/community/pull/4"},
+	}
+	if !reflect.DeepEqual(evidence, expectedEvidence) {
+		t.Errorf("evidence: got %+v, want %+v", evidence, expectedEvidence)
+	}
+}
+
+func TestNewPromotionEvidenceOnlyCountsSubmittedReviews(t *testing.T) {
+	window := ReportWindow{
+		Since: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	reviewed := func(url, state string, submittedAt time.Time) PullRequestReviewNodeItem {
+		return PullRequestReviewNodeItem{PullRequestReview: PullRequestReviewFragment{
+			URL:     url,
+			Reviews: PullRequestReviews{Nodes: []PullRequestReviewItem{{State: state, SubmittedAt: submittedAt}}},
+		}}
+	}
+	report := &UserContributionReportForRepository{
+		UserName: "testuser",
+		Window:   window,
+		UserContributions: UserContributions{
+			PullRequestsReviewed: PullRequestsReviewed{Nodes: []PullRequestReviewNodeItem{
+				reviewed("https://github.com/kubevirt/community/pull/1", "PENDING", time.Time{}),
+				reviewed("https://github.com/kubevirt/community/pull/2", "COMMENTED", time.Date(2024, 9, 30, 23, 0, 0, 0, time.UTC)),
+				reviewed("https://github.com/kubevirt/community/pull/3", "COMMENTED", time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)),
+			}},
+		},
+	}
+
+	evidence := NewPromotionEvidence(report)
+
+	expectedReviewed := []string{"https://github.com/kubevirt/community/pull/3"}
+	if !reflect.DeepEqual(evidence.Reviewed, expectedReviewed) {
+		t.Errorf("reviewed: got %v, want %v", evidence.Reviewed, expectedReviewed)
+	}
+}
+
+func TestScoringConfigMeetsPromotionBar(t *testing.T) {
+	config := ScoringConfig{
+		Weights:    map[ActivityType]float64{PullRequestsReviewedActivity: 1},
+		Thresholds: map[Role]float64{ReviewerRole: 2},
+		PromotionBars: map[Role]PromotionBar{
+			ReviewerRole: {ApprovedMerged: 1, ReviewedOrAuthored: 3},
+		},
+	}
+	evidence := &PromotionEvidence{
+		Reviewed:       []string{"pull/1", "pull/2"},
+		ApprovedMerged: []string{"pull/1"},
+		Authored:       []string{"pull/2", "pull/3"},
+	}
+	testCases := []struct {
+		name            string
+		role            Role
+		activityCounts  ActivityCounts
+		evidence        *PromotionEvidence
+		expectedMeets   bool
+		expectedReasons int
+	}{
+		{
+			name:           "meets bar",
+			role:           ReviewerRole,
+			activityCounts: ActivityCounts{PullRequestsReviewedActivity: 2},
+			evidence:       evidence,
+			expectedMeets:  true,
+		},
+		{
+			name:            "score below threshold",
+			role:            ReviewerRole,
+			activityCounts:  ActivityCounts{PullRequestsReviewedActivity: 1},
+			evidence:        evidence,
+			expectedReasons: 1,
+		},
+		{
+			name:            "pull requests counted once",
+			role:            ReviewerRole,
+			activityCounts:  ActivityCounts{PullRequestsReviewedActivity: 2},
+			evidence:        &PromotionEvidence{Reviewed: []string{"pull/1", "pull/2"}, ApprovedMerged: []string{"pull/1"}, Authored: []string{"pull/2"}},
+			expectedReasons: 1,
+		},
+		{
+			name:            "nothing approved",
+			role:            ReviewerRole,
+			activityCounts:  ActivityCounts{PullRequestsReviewedActivity: 2},
+			evidence:        &PromotionEvidence{Reviewed: []string{"pull/1", "pull/2", "pull/3"}},
+			expectedReasons: 1,
+		},
+		{
+			name:            "role without bar",
+			role:            ApproverRole,
+			activityCounts:  ActivityCounts{PullRequestsReviewedActivity: 2},
+			evidence:        evidence,
+			expectedReasons: 1,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			meets, reasons := config.MeetsPromotionBar(testCase.role, testCase.activityCounts, testCase.evidence)
+			if meets != testCase.expectedMeets || len(reasons) != testCase.expectedReasons {
+				t.Errorf("got %t with reasons %v, want %t with %d reasons", meets, reasons, testCase.expectedMeets, testCase.expectedReasons)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"context"
+	"fmt"
+	"github.com/shurcooL/githubv4"
+	"golang.org/x/oauth2"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Querier executes GitHub GraphQL queries. It is satisfied by
+// *githubv4.Client, which is what is used if no Querier is set in the
+// ContributionReportGeneratorOptions.
+type Querier interface {
+	Query(ctx context.Context, q interface{}, variables map[string]interface{}) error
+}
+
+// NewGithubQuerier creates a Querier for the GitHub GraphQL API that
+// authenticates with the token read from githubTokenPath.
+func NewGithubQuerier(githubTokenPath string) (Querier, error) {
+	token, err := os.ReadFile(githubTokenPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use github token path %s: %v", githubTokenPath, err)
+	}
+	src := oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: strings.TrimSpace(string(token))},
+	)
+	httpClient := oauth2.NewClient(context.Background(), src)
+	return newGithubClient(githubGraphQLURL, httpClient), nil
+}
+
+const githubGraphQLURL = "https://api.github.com/graphql"
+
+// newGithubClient creates a client for the GraphQL API at url, whose
+// failed requests are returned as *QueryError.
+func newGithubClient(url string, httpClient *http.Client) Querier {
+	transport := httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	classifyingClient := *httpClient
+	classifyingClient.Transport = classifyingTransport{transport: transport}
+	return githubv4.NewEnterpriseClient(url, &classifyingClient)
+}
@@ -0,0 +1,123 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"context"
+	log "github.com/sirupsen/logrus"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// RateLimit is the state of the GitHub GraphQL API rate limit as returned
+// with each query.
+type RateLimit struct {
+	Remaining int
+	ResetAt   time.Time
+	Cost      int
+}
+
+// rateLimitingQuerier requests the rate limit along with every query it
+// runs. If the remaining points of the last known rate limit are below
+// the threshold, queries are held back until the rate limit is reset.
+// It is safe for concurrent use.
+type rateLimitingQuerier struct {
+	querier   Querier
+	threshold int
+
+	lock      sync.Mutex
+	rateLimit RateLimit
+}
+
+func newRateLimitingQuerier(querier Querier, threshold int) *rateLimitingQuerier {
+	return &rateLimitingQuerier{querier: querier, threshold: threshold}
+}
+
+func (r *rateLimitingQuerier) Query(ctx context.Context, q interface{}, variables map[string]interface{}) error {
+	queryValue := reflect.ValueOf(q)
+	if queryValue.Kind() != reflect.Ptr || queryValue.Elem().Kind() != reflect.Struct {
+		return r.querier.Query(ctx, q, variables)
+	}
+
+	err := r.waitForReset(ctx)
+	if err != nil {
+		return err
+	}
+
+	// wrap the query so that the rate limit is requested alongside it,
+	// i.e. `{... on Query{<query>},rateLimit{remaining,resetAt,cost}}`
+	wrapped := reflect.New(reflect.StructOf([]reflect.StructField{
+		{
+			Name: "Query",
+			Type: queryValue.Elem().Type(),
+			Tag:  `graphql:"... on Query"`,
+		},
+		{
+			Name: "RateLimit",
+			Type: reflect.TypeOf(RateLimit{}),
+			Tag:  `graphql:"rateLimit"`,
+		},
+	}))
+	err = r.querier.Query(ctx, wrapped.Interface(), variables)
+	queryValue.Elem().Set(wrapped.Elem().Field(0))
+	r.update(wrapped.Elem().Field(1).Interface().(RateLimit))
+	return err
+}
+
+func (r *rateLimitingQuerier) update(rateLimit RateLimit) {
+	if rateLimit.ResetAt.IsZero() {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.rateLimit = rateLimit
+	log.Tracef("rate limit: remaining %d, reset at %s, cost %d", rateLimit.Remaining, rateLimit.ResetAt.Format(time.DateTime), rateLimit.Cost)
+}
+
+func (r *rateLimitingQuerier) waitForReset(ctx context.Context) error {
+	r.lock.Lock()
+	rateLimit := r.rateLimit
+	r.lock.Unlock()
+
+	if rateLimit.ResetAt.IsZero() || rateLimit.Remaining >= r.threshold {
+		return nil
+	}
+	waitDuration := time.Until(rateLimit.ResetAt)
+	if waitDuration > 0 {
+		log.Infof("rate limit remaining %d is below %d, waiting until reset at %s", rateLimit.Remaining, r.threshold, rateLimit.ResetAt.Format(time.DateTime))
+		timer := time.NewTimer(waitDuration)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	// the rate limit is unknown after the reset, the next query will
+	// update it
+	r.lock.Lock()
+	if r.rateLimit.ResetAt.Equal(rateLimit.ResetAt) {
+		r.rateLimit = RateLimit{}
+	}
+	r.lock.Unlock()
+	return nil
+}
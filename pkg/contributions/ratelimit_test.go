@@ -0,0 +1,106 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimitingQuerier_Query(t *testing.T) {
+	querier := newRateLimitingQuerier(newFixtureQuerier(t, "rate-limit"), 10)
+
+	user, err := queryUser(context.Background(), querier, "testuser")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.ID != "U_testuser" {
+		t.Errorf("user id: got %q, want %q", user.ID, "U_testuser")
+	}
+	expectedRateLimit := RateLimit{
+		Remaining: 42,
+		ResetAt:   time.Date(2024, 12, 5, 16, 0, 0, 0, time.UTC),
+		Cost:      1,
+	}
+	if querier.rateLimit != expectedRateLimit {
+		t.Errorf("rate limit: got %+v, want %+v", querier.rateLimit, expectedRateLimit)
+	}
+}
+
+func TestRateLimitingQuerier_waitForReset(t *testing.T) {
+	testCases := []struct {
+		name          string
+		remaining     int
+		resetIn       time.Duration
+		cancelContext bool
+		expectedWait  time.Duration
+		expectedErr   bool
+	}{
+		{
+			name:         "above threshold",
+			remaining:    100,
+			resetIn:      time.Hour,
+			expectedWait: 0,
+		},
+		{
+			name:         "below threshold",
+			remaining:    5,
+			resetIn:      50 * time.Millisecond,
+			expectedWait: 50 * time.Millisecond,
+		},
+		{
+			name:          "below threshold with canceled context",
+			remaining:     5,
+			resetIn:       time.Hour,
+			cancelContext: true,
+			expectedErr:   true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			querier := newRateLimitingQuerier(nil, 10)
+			querier.rateLimit = RateLimit{
+				Remaining: testCase.remaining,
+				ResetAt:   time.Now().Add(testCase.resetIn),
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			if testCase.cancelContext {
+				cancel()
+			} else {
+				defer cancel()
+			}
+
+			start := time.Now()
+			err := querier.waitForReset(ctx)
+			waited := time.Since(start)
+
+			if (err != nil) != testCase.expectedErr {
+				t.Errorf("error: got %v, expected error %t", err, testCase.expectedErr)
+			}
+			if waited < testCase.expectedWait {
+				t.Errorf("waited %s, expected at least %s", waited, testCase.expectedWait)
+			}
+			if testCase.expectedWait == 0 && waited > 10*time.Millisecond {
+				t.Errorf("waited %s, expected no wait", waited)
+			}
+		})
+	}
+}
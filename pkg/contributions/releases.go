@@ -0,0 +1,161 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"context"
+	"fmt"
+	"github.com/shurcooL/githubv4"
+	"strings"
+)
+
+// releasesPage is a page of the releases of a repository, ordered by
+// creation time, newest first.
+type releasesPage struct {
+	PageInfo PageInfo
+	Nodes    []ReleaseNode
+}
+
+// last returns whether the page contains the oldest release that needs to
+// be looked at, since all later releases have been created before the
+// window.
+func (p releasesPage) last(window ReportWindow) bool {
+	return len(p.Nodes) == 0 || p.Nodes[len(p.Nodes)-1].CreatedAt.Before(window.Since)
+}
+
+// add adds the releases of the page that the user published within the
+// window, drafts are ignored.
+func (r *ReleasesCreated) add(page releasesPage, userName string, window ReportWindow) {
+	for _, release := range page.Nodes {
+		if release.IsDraft || !strings.EqualFold(release.Author.Login, userName) || !window.contains(release.PublishedAt) {
+			continue
+		}
+		r.Nodes = append(r.Nodes, release)
+		r.TotalCount++
+	}
+}
+
+// queryReleasesByUser returns the releases of the repository that the user
+// published within the window. Releases are fetched until one has been
+// created before the window.
+func queryReleasesByUser(ctx context.Context, client Querier, org, repo, userName string, window ReportWindow, maxPages int) (ReleasesCreated, error) {
+	var releasesCreated ReleasesCreated
+	page, err := fetchReleases(ctx, client, org, repo, nil)
+	if err != nil {
+		return ReleasesCreated{}, err
+	}
+	releasesCreated.add(page, userName, window)
+	err = fetchRemainingReleases(ctx, client, org, repo, userName, window, page, &releasesCreated, maxPages)
+	if err != nil {
+		return ReleasesCreated{}, err
+	}
+	return releasesCreated, nil
+}
+
+// fetchRemainingReleases fetches the pages of releases that follow the
+// given page, until one has been created before the window.
+func fetchRemainingReleases(ctx context.Context, client Querier, org, repo, userName string, window ReportWindow, page releasesPage, releasesCreated *ReleasesCreated, maxPages int) error {
+	if page.last(window) {
+		return nil
+	}
+	return walkPages(page.PageInfo, maxPages, func(cursor *githubv4.String) (PageInfo, error) {
+		page, err := fetchReleases(ctx, client, org, repo, cursor)
+		if err != nil {
+			return PageInfo{}, err
+		}
+		releasesCreated.add(page, userName, window)
+		if page.last(window) {
+			return PageInfo{}, nil
+		}
+		return page.PageInfo, nil
+	})
+}
+
+func fetchReleases(ctx context.Context, client Querier, org, repo string, cursor *githubv4.String) (releasesPage, error) {
+	var query struct {
+		ReleasesByUser struct {
+			Releases releasesPage `graphql:"releases(first: 25, after: $cursor, orderBy: {field: CREATED_AT, direction: DESC})"`
+		} `graphql:"releasesByUser: repository(owner: $org, name: $repo)"`
+	}
+	variables := map[string]interface{}{
+		"org":    githubv4.String(org),
+		"repo":   githubv4.String(repo),
+		"cursor": cursor,
+	}
+	err := client.Query(ctx, &query, variables)
+	if err != nil {
+		return releasesPage{}, fmt.Errorf("failed to use github query %+v with variables %v: %w", query, variables, err)
+	}
+	return query.ReleasesByUser.Releases, nil
+}
+
+// queryReleasesByUserInOrg returns the releases of the public repositories
+// of the organization that the user published within the window, where the
+// releases of a repository are fetched page by page only if its first
+// page doesn't reach back to the start of the window.
+func queryReleasesByUserInOrg(ctx context.Context, client Querier, org, userName string, window ReportWindow, includeArchived bool, maxPages int) (ReleasesCreated, error) {
+	var isArchived *githubv4.Boolean
+	if !includeArchived {
+		isArchived = githubv4.NewBoolean(false)
+	}
+	var releasesCreated ReleasesCreated
+	fetchPage := func(cursor *githubv4.String) (PageInfo, error) {
+		var query struct {
+			ReleasesByUserInOrg struct {
+				Repositories struct {
+					PageInfo PageInfo
+					Nodes    []struct {
+						Name     string
+						Releases releasesPage `graphql:"releases(first: 25, orderBy: {field: CREATED_AT, direction: DESC})"`
+					}
+				} `graphql:"repositories(first: 25, after: $cursor, isArchived: $isArchived, visibility: PUBLIC, orderBy: {field: NAME, direction: ASC})"`
+			} `graphql:"releasesByUserInOrg: organization(login: $org)"`
+		}
+		variables := map[string]interface{}{
+			"org":        githubv4.String(org),
+			"isArchived": isArchived,
+			"cursor":     cursor,
+		}
+		err := client.Query(ctx, &query, variables)
+		if err != nil {
+			return PageInfo{}, fmt.Errorf("failed to use github query %+v with variables %v: %w", query, variables, err)
+		}
+		repositories := query.ReleasesByUserInOrg.Repositories
+		for _, repository := range repositories.Nodes {
+			releasesCreated.add(repository.Releases, userName, window)
+			err = fetchRemainingReleases(ctx, client, org, repository.Name, userName, window, repository.Releases, &releasesCreated, maxPages)
+			if err != nil {
+				return PageInfo{}, err
+			}
+		}
+		return repositories.PageInfo, nil
+	}
+	pageInfo, err := fetchPage(nil)
+	if err != nil {
+		return ReleasesCreated{}, err
+	}
+	// all repositories are always scanned, maxPages only limits the releases
+	// fetched per repository
+	err = walkPages(pageInfo, 0, fetchPage)
+	if err != nil {
+		return ReleasesCreated{}, err
+	}
+	return releasesCreated, nil
+}
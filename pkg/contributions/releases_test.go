@@ -0,0 +1,118 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+package contributions
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReportDiscussionsAndReleases(t *testing.T) {
+	generator := newTestGenerator(t, "repository", ContributionReportGeneratorOptions{
+		Org:   "kubevirt",
+		Repo:  "community",
+		Since: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	report, err := generator.GenerateReport(context.Background(), "testuser")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repoReport := report.(*UserContributionReportForRepository)
+
+	activityCounts := report.ActivityCounts()
+	counts := []struct {
+		activityType ActivityType
+		want         int
+	}{
+		{DiscussionsCreatedActivity, 1},
+		{DiscussionsAnsweredActivity, 1},
+		{DiscussionsCommentedActivity, 2},
+		{ReleasesCreatedActivity, 1},
+	}
+	for _, count := range counts {
+		if activityCounts[count.activityType] != count.want {
+			t.Errorf("%s: got %d, want %d", count.activityType, activityCounts[count.activityType], count.want)
+		}
+	}
+	expectedReleases := []string{"https://github.com/kubevirt/community/releases/tag/v1.1.0"}
+	var releases []string
+	for _, release := range repoReport.ReleasesCreated.Nodes {
+		releases = append(releases, release.URL)
+	}
+	if !reflect.DeepEqual(releases, expectedReleases) {
+		t.Errorf("releases: got %v, want %v", releases, expectedReleases)
+	}
+	expectedAnswered := []string{"https://github.com/kubevirt/community/discussions/11"}
+	answered := repoReport.discussionsAnswered(repoReport.UserName, repoReport.Window)
+	if !reflect.DeepEqual(answered, expectedAnswered) {
+		t.Errorf("discussions answered: got %v, want %v", answered, expectedAnswered)
+	}
+}
+
+func TestReleasesCreatedAdd(t *testing.T) {
+	window := ReportWindow{
+		Since: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	inWindow := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		release  ReleaseNode
+		expected int
+	}{
+		{
+			name:     "published by user",
+			release:  ReleaseNode{Author: Author{Login: "TestUser"}, PublishedAt: inWindow},
+			expected: 1,
+		},
+		{
+			name:     "draft",
+			release:  ReleaseNode{Author: Author{Login: "testuser"}, PublishedAt: inWindow, IsDraft: true},
+			expected: 0,
+		},
+		{
+			name:     "published by other user",
+			release:  ReleaseNode{Author: Author{Login: "otheruser"}, PublishedAt: inWindow},
+			expected: 0,
+		},
+		{
+			name:     "published before window",
+			release:  ReleaseNode{Author: Author{Login: "testuser"}, PublishedAt: window.Since.Add(-time.Second)},
+			expected: 0,
+		},
+		{
+			name:     "published at end of window",
+			release:  ReleaseNode{Author: Author{Login: "testuser"}, PublishedAt: window.Until},
+			expected: 0,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var releasesCreated ReleasesCreated
+			releasesCreated.add(releasesPage{Nodes: []ReleaseNode{testCase.release}}, "testuser", window)
+			if releasesCreated.TotalCount != testCase.expected || len(releasesCreated.Nodes) != testCase.expected {
+				t.Errorf("got %d releases, want %d", releasesCreated.TotalCount, testCase.expected)
+			}
+		})
+	}
+}
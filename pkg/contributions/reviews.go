@@ -0,0 +1,108 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"fmt"
+)
+
+// Review states as reported by the GitHub API
+const (
+	reviewStateApproved         = "APPROVED"
+	reviewStateChangesRequested = "CHANGES_REQUESTED"
+	reviewStateCommented        = "COMMENTED"
+	reviewStateDismissed        = "DISMISSED"
+)
+
+// ReviewDetails breaks down the reviews that a user has submitted within
+// the report window by their state. ReviewComments is the number of inline
+// comments of those reviews, ApprovedMerged holds the URLs of the pull
+// requests that the user approved and that were merged.
+type ReviewDetails struct {
+	Approved         int      `yaml:"approved"`
+	ChangesRequested int      `yaml:"changesRequested"`
+	Commented        int      `yaml:"commented"`
+	Dismissed        int      `yaml:"dismissed"`
+	ReviewComments   int      `yaml:"reviewComments"`
+	ApprovedMerged   []string `yaml:"approvedMerged"`
+}
+
+// reviewDetails collects the review details from the reviewed pull
+// requests, where reviews submitted outside the window are ignored.
+func (u *UserContributions) reviewDetails(window ReportWindow) *ReviewDetails {
+	details := &ReviewDetails{}
+	for _, node := range u.PullRequestsReviewed.Nodes {
+		pullRequest := node.PullRequestReview
+		approved := false
+		for _, review := range pullRequest.Reviews.Nodes {
+			if !review.SubmittedAt.IsZero() && !window.contains(review.SubmittedAt) {
+				continue
+			}
+			switch review.State {
+			case reviewStateApproved:
+				details.Approved++
+				approved = true
+			case reviewStateChangesRequested:
+				details.ChangesRequested++
+			case reviewStateCommented:
+				details.Commented++
+			case reviewStateDismissed:
+				details.Dismissed++
+			}
+			details.ReviewComments += review.Comments.TotalCount
+		}
+		if approved && pullRequest.Merged {
+			details.ApprovedMerged = append(details.ApprovedMerged, pullRequest.URL)
+		}
+	}
+	return details
+}
+
+func (r *ReviewDetails) merge(other *ReviewDetails) {
+	r.Approved += other.Approved
+	r.ChangesRequested += other.ChangesRequested
+	r.Commented += other.Commented
+	r.Dismissed += other.Dismissed
+	r.ReviewComments += other.ReviewComments
+	r.ApprovedMerged = append(r.ApprovedMerged, other.ApprovedMerged...)
+}
+
+func (r *ReviewDetails) summary() string {
+	return fmt.Sprintf(`
+    reviews:
+        approved:          %d
+        changes requested: %d
+        commented:         %d
+        dismissed:         %d
+        review comments:   %d
+        approved merged:   %d
+`, r.Approved, r.ChangesRequested, r.Commented, r.Dismissed, r.ReviewComments, len(r.ApprovedMerged))
+}
+
+func (u *UserContributionReportForScopes) reviewDetails() *ReviewDetails {
+	details := &ReviewDetails{}
+	for _, report := range u.RepositoryReports {
+		details.merge(report.reviewDetails(report.Window))
+	}
+	for _, report := range u.OrganizationReports {
+		details.merge(report.reviewDetails(report.Window))
+	}
+	return details
+}
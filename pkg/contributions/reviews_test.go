@@ -0,0 +1,73 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReviewDetails(t *testing.T) {
+	window := ReportWindow{
+		Since: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	pullRequest := func(url string, merged bool, reviews ...PullRequestReviewItem) PullRequestReviewNodeItem {
+		return PullRequestReviewNodeItem{PullRequestReview: PullRequestReviewFragment{
+			URL:     url,
+			Merged:  merged,
+			Reviews: PullRequestReviews{Nodes: reviews},
+		}}
+	}
+	review := func(state string, submittedAt time.Time, comments int) PullRequestReviewItem {
+		return PullRequestReviewItem{State: state, SubmittedAt: submittedAt, Comments: PullRequestReviewComments{TotalCount: comments}}
+	}
+	inWindow := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	beforeWindow := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	userContributions := &UserContributions{
+		PullRequestsReviewed: PullRequestsReviewed{Nodes: []PullRequestReviewNodeItem{
+			pullRequest("https://github.com/kubevirt/kubevirt/pull/1", true,
+				review(reviewStateChangesRequested, inWindow, 2),
+				review(reviewStateApproved, inWindow, 1),
+			),
+			pullRequest("https://github.com/kubevirt/kubevirt/pull/2", false,
+				review(reviewStateApproved, inWindow, 0),
+			),
+			pullRequest("https://github.com/kubevirt/kubevirt/pull/3", true,
+				review(reviewStateApproved, beforeWindow, 4),
+				review(reviewStateCommented, inWindow, 1),
+			),
+		}},
+	}
+
+	expected := &ReviewDetails{
+		Approved:         2,
+		ChangesRequested: 1,
+		Commented:        1,
+		ReviewComments:   4,
+		ApprovedMerged:   []string{"https://github.com/kubevirt/kubevirt/pull/1"},
+	}
+	details := userContributions.reviewDetails(window)
+	if !reflect.DeepEqual(details, expected) {
+		t.Errorf("got %+v, want %+v", details, expected)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scope is what contributions are searched in, which is either a
+// repository or, if Repo is empty, an organization.
+type Scope struct {
+	Org  string `yaml:"org"`
+	Repo string `yaml:"repo,omitempty"`
+}
+
+// ParseScope parses a scope from either `org` or `org/repo`.
+func ParseScope(scope string) (Scope, error) {
+	parts := strings.Split(scope, "/")
+	for _, part := range parts {
+		if part == "" {
+			return Scope{}, fmt.Errorf("invalid scope %q, expected format org or org/repo", scope)
+		}
+	}
+	switch len(parts) {
+	case 1:
+		return Scope{Org: parts[0]}, nil
+	case 2:
+		return Scope{Org: parts[0], Repo: parts[1]}, nil
+	default:
+		return Scope{}, fmt.Errorf("invalid scope %q, expected format org or org/repo", scope)
+	}
+}
+
+func (s Scope) String() string {
+	if s.Repo == "" {
+		return s.Org
+	}
+	return fmt.Sprintf("%s/%s", s.Org, s.Repo)
+}
+
+// searchQualifier returns the qualifier that restricts a search to the scope.
+func (s Scope) searchQualifier() string {
+	if s.Repo == "" {
+		return fmt.Sprintf("org:%s", s.Org)
+	}
+	return fmt.Sprintf("repo:%s/%s", s.Org, s.Repo)
+}
+
+// contains returns whether the activity in other is searched in the scope
+// as well, that is if both are the same or other is a repository of the
+// organization.
+func (s Scope) contains(other Scope) bool {
+	if !strings.EqualFold(s.Org, other.Org) {
+		return false
+	}
+	return s.Repo == "" || strings.EqualFold(s.Repo, other.Repo)
+}
+
+// validateScopes returns an error if any of the scopes overlap, since the
+// activity within both would be counted twice for the aggregated report.
+func validateScopes(scopes []Scope) error {
+	for i, scope := range scopes {
+		for _, other := range scopes[i+1:] {
+			if scope.contains(other) || other.contains(scope) {
+				return fmt.Errorf("scopes %s and %s overlap", scope, other)
+			}
+		}
+	}
+	return nil
+}
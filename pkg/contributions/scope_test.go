@@ -0,0 +1,104 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import "testing"
+
+func TestParseScope(t *testing.T) {
+	testCases := []struct {
+		name          string
+		scope         string
+		expected      Scope
+		expectedError bool
+	}{
+		{
+			name:     "organization",
+			scope:    "kubevirt",
+			expected: Scope{Org: "kubevirt"},
+		},
+		{
+			name:     "repository",
+			scope:    "kubevirt/community",
+			expected: Scope{Org: "kubevirt", Repo: "community"},
+		},
+		{
+			name:          "empty repository",
+			scope:         "kubevirt/",
+			expectedError: true,
+		},
+		{
+			name:          "too many parts",
+			scope:         "kubevirt/community/OWNERS",
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			scope, err := ParseScope(testCase.scope)
+			if testCase.expectedError != (err != nil) {
+				t.Fatalf("expected error %t, got %v", testCase.expectedError, err)
+			}
+			if scope != testCase.expected {
+				t.Errorf("got %+v, want %+v", scope, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestValidateScopes(t *testing.T) {
+	testCases := []struct {
+		name          string
+		scopes        []Scope
+		expectedError bool
+	}{
+		{
+			name:   "distinct scopes",
+			scopes: []Scope{{Org: "kubevirt", Repo: "community"}, {Org: "kubevirt", Repo: "kubevirt"}, {Org: "nmstate"}},
+		},
+		{
+			name:          "same organization twice",
+			scopes:        []Scope{{Org: "kubevirt"}, {Org: "nmstate"}, {Org: "kubevirt"}},
+			expectedError: true,
+		},
+		{
+			name:          "same repository twice",
+			scopes:        []Scope{{Org: "kubevirt", Repo: "community"}, {Org: "KubeVirt", Repo: "Community"}},
+			expectedError: true,
+		},
+		{
+			name:          "repository of a listed organization",
+			scopes:        []Scope{{Org: "kubevirt"}, {Org: "kubevirt", Repo: "community"}},
+			expectedError: true,
+		},
+		{
+			name:          "organization of a listed repository",
+			scopes:        []Scope{{Org: "kubevirt", Repo: "community"}, {Org: "kubevirt"}},
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateScopes(testCase.scopes)
+			if testCase.expectedError != (err != nil) {
+				t.Errorf("expected error %t, got %v", testCase.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"fmt"
+)
+
+// Role is the role of a user in the community, which determines the bar of
+// activity that the user is checked against.
+type Role string
+
+const (
+	OrgMemberRole Role = "orgMember"
+	ReviewerRole  Role = "reviewer"
+	ApproverRole  Role = "approver"
+	SigChairRole  Role = "sigChair"
+)
+
+// Roles holds all roles in ascending order of the expected activity.
+var Roles = []Role{
+	OrgMemberRole,
+	ReviewerRole,
+	ApproverRole,
+	SigChairRole,
+}
+
+// ScoringConfig determines how the activity of a user is scored, where
+// each contribution adds the weight of its activity type to the score. A
+// user is considered active in a role if the score reaches the threshold
+// of the role. PromotionBars holds the review and authorship activity that
+// a user needs to show in addition to be nominated for a role.
+type ScoringConfig struct {
+	Weights       map[ActivityType]float64 `yaml:"weights"`
+	Thresholds    map[Role]float64         `yaml:"thresholds"`
+	PromotionBars map[Role]PromotionBar    `yaml:"promotionBars"`
+}
+
+// DefaultScoringConfig returns the scoring configuration that is used if
+// none is configured. The threshold for org members is met by a single
+// contribution, as membership_policy.md only asks for any contribution.
+// Reviewers and approvers are expected to mainly review pull requests,
+// either through GitHub reviews or through the Prow commands /lgtm and
+// /approve. The promotion bars follow the requirements for reviewers and
+// approvers from membership_policy.md, where being the primary reviewer of
+// a pull request is taken as having approved it.
+func DefaultScoringConfig() ScoringConfig {
+	return ScoringConfig{
+		Weights: map[ActivityType]float64{
+			IssuesCreatedActivity:         1,
+			IssuesCommentedActivity:       1,
+			PullRequestsCreatedActivity:   2,
+			PullRequestsReviewedActivity:  3,
+			PullRequestsCommentedActivity: 1,
+			CommitsActivity:               1,
+			DiscussionsCreatedActivity:    1,
+			DiscussionsAnsweredActivity:   2,
+			DiscussionsCommentedActivity:  1,
+			ReleasesCreatedActivity:       3,
+			MailsSentActivity:             1,
+			MailThreadsStartedActivity:    1,
+			SlackMessagesActivity:         1,
+			SlackThreadsAnsweredActivity:  1,
+			LGTMCommandsActivity:          2,
+			ApproveCommandsActivity:       3,
+			HoldCommandsActivity:          1,
+			RetestCommandsActivity:        0,
+		},
+		Thresholds: map[Role]float64{
+			OrgMemberRole: 1,
+			ReviewerRole:  10,
+			ApproverRole:  20,
+			SigChairRole:  10,
+		},
+		PromotionBars: map[Role]PromotionBar{
+			ReviewerRole: {ApprovedMerged: 5, ReviewedOrAuthored: 20},
+			ApproverRole: {ApprovedMerged: 10, ReviewedOrAuthored: 30},
+		},
+	}
+}
+
+// Validate checks that only known activity types and roles are configured,
+// and that neither weights, thresholds nor promotion bars are negative.
+func (c ScoringConfig) Validate() error {
+	knownActivityTypes := make(map[ActivityType]struct{}, len(ActivityTypes))
+	for _, activityType := range ActivityTypes {
+		knownActivityTypes[activityType] = struct{}{}
+	}
+	for activityType, weight := range c.Weights {
+		if _, known := knownActivityTypes[activityType]; !known {
+			return fmt.Errorf("unknown activity type %q", activityType)
+		}
+		if weight < 0 {
+			return fmt.Errorf("weight of %q must not be negative", activityType)
+		}
+	}
+	knownRoles := make(map[Role]struct{}, len(Roles))
+	for _, role := range Roles {
+		knownRoles[role] = struct{}{}
+	}
+	for role, threshold := range c.Thresholds {
+		if _, known := knownRoles[role]; !known {
+			return fmt.Errorf("unknown role %q", role)
+		}
+		if threshold < 0 {
+			return fmt.Errorf("threshold of %q must not be negative", role)
+		}
+	}
+	for role, bar := range c.PromotionBars {
+		if _, known := knownRoles[role]; !known {
+			return fmt.Errorf("unknown role %q", role)
+		}
+		if bar.ApprovedMerged < 0 || bar.ReviewedOrAuthored < 0 {
+			return fmt.Errorf("promotion bar of %q must not be negative", role)
+		}
+	}
+	return nil
+}
+
+// Score returns the weighted sum of the activity counts.
+func (c ScoringConfig) Score(activityCounts ActivityCounts) float64 {
+	score := 0.0
+	for activityType, count := range activityCounts {
+		score += c.Weights[activityType] * float64(count)
+	}
+	return score
+}
+
+// Threshold returns the score that a user in the role needs to reach. If
+// no threshold is configured for the role, the one for org members applies.
+func (c ScoringConfig) Threshold(role Role) float64 {
+	threshold, exists := c.Thresholds[role]
+	if !exists {
+		return c.Thresholds[OrgMemberRole]
+	}
+	return threshold
+}
+
+// IsActive returns whether the score of the activity counts reaches the
+// threshold of the role.
+func (c ScoringConfig) IsActive(activityCounts ActivityCounts, role Role) bool {
+	return c.Score(activityCounts) >= c.Threshold(role)
+}
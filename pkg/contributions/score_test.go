@@ -0,0 +1,127 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"testing"
+)
+
+func TestScoringConfigIsActive(t *testing.T) {
+	activityCounts := ActivityCounts{
+		IssuesCommentedActivity:      1,
+		PullRequestsReviewedActivity: 2,
+		CommitsActivity:              1,
+	}
+	testCases := []struct {
+		name           string
+		config         ScoringConfig
+		role           Role
+		expectedScore  float64
+		expectedActive bool
+	}{
+		{
+			name:           "org member with default config",
+			config:         DefaultScoringConfig(),
+			role:           OrgMemberRole,
+			expectedScore:  8,
+			expectedActive: true,
+		},
+		{
+			name:           "approver with default config",
+			config:         DefaultScoringConfig(),
+			role:           ApproverRole,
+			expectedScore:  8,
+			expectedActive: false,
+		},
+		{
+			name: "reviewer reaching threshold",
+			config: ScoringConfig{
+				Weights:    map[ActivityType]float64{PullRequestsReviewedActivity: 5},
+				Thresholds: map[Role]float64{ReviewerRole: 10},
+			},
+			role:           ReviewerRole,
+			expectedScore:  10,
+			expectedActive: true,
+		},
+		{
+			name: "role without threshold falls back to org member",
+			config: ScoringConfig{
+				Weights:    map[ActivityType]float64{CommitsActivity: 1},
+				Thresholds: map[Role]float64{OrgMemberRole: 2},
+			},
+			role:           SigChairRole,
+			expectedScore:  1,
+			expectedActive: false,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			score := testCase.config.Score(activityCounts)
+			if score != testCase.expectedScore {
+				t.Errorf("score: got %v, want %v", score, testCase.expectedScore)
+			}
+			active := testCase.config.IsActive(activityCounts, testCase.role)
+			if active != testCase.expectedActive {
+				t.Errorf("active: got %t, want %t", active, testCase.expectedActive)
+			}
+		})
+	}
+}
+
+func TestScoringConfigValidate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		config        ScoringConfig
+		expectedError bool
+	}{
+		{
+			name:   "default config",
+			config: DefaultScoringConfig(),
+		},
+		{
+			name:          "unknown activity type",
+			config:        ScoringConfig{Weights: map[ActivityType]float64{"stars": 1}},
+			expectedError: true,
+		},
+		{
+			name:          "unknown role",
+			config:        ScoringConfig{Thresholds: map[Role]float64{"maintainer": 1}},
+			expectedError: true,
+		},
+		{
+			name:          "negative weight",
+			config:        ScoringConfig{Weights: map[ActivityType]float64{CommitsActivity: -1}},
+			expectedError: true,
+		},
+		{
+			name:          "negative promotion bar",
+			config:        ScoringConfig{PromotionBars: map[Role]PromotionBar{ReviewerRole: {ApprovedMerged: -1}}},
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.config.Validate()
+			if testCase.expectedError != (err != nil) {
+				t.Errorf("expected error %t, got %v", testCase.expectedError, err)
+			}
+		})
+	}
+}
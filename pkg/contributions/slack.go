@@ -0,0 +1,261 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+package contributions
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SlackMessage is a message of a Slack export, where only the fields that
+// are needed to attribute the message to a user are kept.
+type SlackMessage struct {
+	Channel   string    `yaml:"channel"`
+	User      string    `yaml:"user"`
+	TS        string    `yaml:"ts"`
+	ThreadTS  string    `yaml:"threadTs,omitempty"`
+	Timestamp time.Time `yaml:"timestamp"`
+}
+
+// isReply returns whether the message has been posted into the thread of
+// another message.
+func (m SlackMessage) isReply() bool {
+	return m.ThreadTS != "" && m.ThreadTS != m.TS
+}
+
+// SlackExport holds the messages of a Slack workspace export.
+type SlackExport struct {
+	Messages []SlackMessage
+
+	// threadAuthors holds the user that started a thread per channel and
+	// thread timestamp.
+	threadAuthors map[string]string
+}
+
+// slackExportMessage is a message as it is stored in the daily files of
+// a channel of a Slack export.
+type slackExportMessage struct {
+	Type     string `json:"type"`
+	Subtype  string `json:"subtype"`
+	User     string `json:"user"`
+	TS       string `json:"ts"`
+	ThreadTS string `json:"thread_ts"`
+}
+
+// countedSlackSubtypes are the message subtypes that are written by a
+// user, all other subtypes (i.e. channel_join or bot_message) are ignored.
+var countedSlackSubtypes = map[string]struct{}{
+	"":                 {},
+	"thread_broadcast": {},
+	"file_share":       {},
+}
+
+// ReadSlackExport reads the messages from the directory of an unpacked
+// Slack export, which holds a directory per channel with a JSON file per
+// day (i.e. `virtualization/2024-11-05.json`). If channels are given, only
+// the messages of these channels are read.
+func ReadSlackExport(dir string, channels []string) (*SlackExport, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read slack export: %v", err)
+	}
+	wantedChannels := make(map[string]struct{}, len(channels))
+	for _, channel := range channels {
+		wantedChannels[strings.TrimPrefix(channel, "#")] = struct{}{}
+	}
+	export := &SlackExport{threadAuthors: make(map[string]string)}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		channel := entry.Name()
+		if _, wanted := wantedChannels[channel]; len(wantedChannels) > 0 && !wanted {
+			continue
+		}
+		dayFiles, err := filepath.Glob(filepath.Join(dir, channel, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, dayFile := range dayFiles {
+			err = export.readDayFile(channel, dayFile)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	sort.SliceStable(export.Messages, func(i, j int) bool {
+		return export.Messages[i].Timestamp.Before(export.Messages[j].Timestamp)
+	})
+	return export, nil
+}
+
+func (e *SlackExport) readDayFile(channel, path string) error {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read slack export: %v", err)
+	}
+	var messages []slackExportMessage
+	err = json.Unmarshal(buf, &messages)
+	if err != nil {
+		return fmt.Errorf("in file %q: %v", path, err)
+	}
+	for _, message := range messages {
+		if _, counted := countedSlackSubtypes[message.Subtype]; message.Type != "message" || !counted || message.User == "" {
+			continue
+		}
+		timestamp, err := parseSlackTimestamp(message.TS)
+		if err != nil {
+			return fmt.Errorf("in file %q: %v", path, err)
+		}
+		if message.ThreadTS == message.TS {
+			e.threadAuthors[channel+"/"+message.ThreadTS] = message.User
+		}
+		e.Messages = append(e.Messages, SlackMessage{
+			Channel:   channel,
+			User:      message.User,
+			TS:        message.TS,
+			ThreadTS:  message.ThreadTS,
+			Timestamp: timestamp,
+		})
+	}
+	return nil
+}
+
+// parseSlackTimestamp parses the timestamp of a message, which is the unix
+// time in seconds with a fraction that makes it unique within a channel.
+func parseSlackTimestamp(ts string) (time.Time, error) {
+	seconds, _, _ := strings.Cut(ts, ".")
+	unixSeconds, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid message timestamp %q: %v", ts, err)
+	}
+	return time.Unix(unixSeconds, 0).UTC(), nil
+}
+
+// SlackMapping maps the Slack user IDs (i.e. `U012AB3CD`) of users to their
+// GitHub handles.
+type SlackMapping map[string]string
+
+// ReadSlackMapping reads the mapping from a yaml file that maps Slack user
+// IDs to GitHub handles.
+func ReadSlackMapping(path string) (SlackMapping, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read slack mapping: %v", err)
+	}
+	var mapping SlackMapping
+	err = yaml.Unmarshal(buf, &mapping)
+	if err != nil {
+		return nil, fmt.Errorf("in file %q: %v", path, err)
+	}
+	return mapping, nil
+}
+
+// SlackChannelActivity holds the number of messages that a user posted in
+// a channel, and the number of threads started by others that the user
+// replied to.
+type SlackChannelActivity struct {
+	Channel         string `yaml:"channel"`
+	Messages        int    `yaml:"messages"`
+	ThreadsAnswered int    `yaml:"threadsAnswered"`
+}
+
+// SlackActivity holds the activity of a user per channel of a Slack
+// workspace within the report window, ordered by channel.
+type SlackActivity struct {
+	Channels []SlackChannelActivity `yaml:"channels,omitempty"`
+}
+
+// activity returns the activity of the Slack users that are mapped to
+// one of the handles of the user within the window.
+func (e *SlackExport) activity(userNames []string, mapping SlackMapping, window ReportWindow) *SlackActivity {
+	userIDs := make(map[string]struct{})
+	for userID, handle := range mapping {
+		if containsFold(userNames, handle) {
+			userIDs[userID] = struct{}{}
+		}
+	}
+	channels := make(map[string]*SlackChannelActivity)
+	answeredThreads := make(map[string]struct{})
+	for _, message := range e.Messages {
+		if _, mapped := userIDs[message.User]; !mapped || !window.contains(message.Timestamp) {
+			continue
+		}
+		channel, exists := channels[message.Channel]
+		if !exists {
+			channel = &SlackChannelActivity{Channel: message.Channel}
+			channels[message.Channel] = channel
+		}
+		channel.Messages++
+		if !message.isReply() {
+			continue
+		}
+		thread := message.Channel + "/" + message.ThreadTS
+		if _, ownThread := userIDs[e.threadAuthors[thread]]; ownThread {
+			continue
+		}
+		if _, answered := answeredThreads[thread]; !answered {
+			answeredThreads[thread] = struct{}{}
+			channel.ThreadsAnswered++
+		}
+	}
+	activity := &SlackActivity{}
+	for _, channel := range channels {
+		activity.Channels = append(activity.Channels, *channel)
+	}
+	sort.Slice(activity.Channels, func(i, j int) bool {
+		return activity.Channels[i].Channel < activity.Channels[j].Channel
+	})
+	return activity
+}
+
+func (a *SlackActivity) messages() int {
+	messages := 0
+	for _, channel := range a.Channels {
+		messages += channel.Messages
+	}
+	return messages
+}
+
+func (a *SlackActivity) threadsAnswered() int {
+	threadsAnswered := 0
+	for _, channel := range a.Channels {
+		threadsAnswered += channel.ThreadsAnswered
+	}
+	return threadsAnswered
+}
+
+func (a *SlackActivity) summary() string {
+	summary := fmt.Sprintf(`    slack:
+        messages:         %d
+        threads answered: %d
+`, a.messages(), a.threadsAnswered())
+	for _, channel := range a.Channels {
+		summary += fmt.Sprintf(`        #%s: messages: %d, threads answered: %d
+`, channel.Channel, channel.Messages, channel.ThreadsAnswered)
+	}
+	return summary
+}
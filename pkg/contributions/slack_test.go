@@ -0,0 +1,138 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+package contributions
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadSlackExport(t *testing.T) {
+	testCases := []struct {
+		name             string
+		channels         []string
+		expectedMessages int
+	}{
+		{
+			name:             "all channels",
+			expectedMessages: 7,
+		},
+		{
+			name:             "selected channels",
+			channels:         []string{"virtualization", "#kubevirt-dev"},
+			expectedMessages: 6,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			export, err := ReadSlackExport("testdata/slack-export", testCase.channels)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(export.Messages) != testCase.expectedMessages {
+				t.Errorf("messages: got %d, want %d", len(export.Messages), testCase.expectedMessages)
+			}
+		})
+	}
+}
+
+func TestSlackActivity(t *testing.T) {
+	export, err := ReadSlackExport("testdata/slack-export", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mapping, err := ReadSlackMapping("testdata/slack-mapping.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	window := ReportWindow{
+		Since: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	testCases := []struct {
+		name             string
+		userName         string
+		expectedChannels []SlackChannelActivity
+	}{
+		{
+			name:     "replies to threads of others and own threads",
+			userName: "testuser",
+			expectedChannels: []SlackChannelActivity{
+				{Channel: "kubevirt-dev", Messages: 1, ThreadsAnswered: 1},
+				{Channel: "virtualization", Messages: 4, ThreadsAnswered: 1},
+			},
+		},
+		{
+			name:     "question only",
+			userName: "otheruser",
+			expectedChannels: []SlackChannelActivity{
+				{Channel: "virtualization", Messages: 1, ThreadsAnswered: 0},
+			},
+		},
+		{
+			name:     "unmapped user",
+			userName: "unknown",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			activity := export.activity([]string{testCase.userName}, mapping, window)
+			if !reflect.DeepEqual(activity.Channels, testCase.expectedChannels) {
+				t.Errorf("got %+v, want %+v", activity.Channels, testCase.expectedChannels)
+			}
+		})
+	}
+}
+
+func TestGenerateReportWithSlack(t *testing.T) {
+	export, err := ReadSlackExport("testdata/slack-export", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mapping, err := ReadSlackMapping("testdata/slack-mapping.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	generator := newTestGenerator(t, "repository", ContributionReportGeneratorOptions{
+		Org:          "kubevirt",
+		Repo:         "community",
+		Since:        time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		Until:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Slack:        export,
+		SlackMapping: mapping,
+	})
+
+	report, err := generator.GenerateReport(context.Background(), "testuser")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	activityCounts := report.ActivityCounts()
+	if activityCounts[SlackMessagesActivity] != 5 {
+		t.Errorf("slack messages: got %d, want %d", activityCounts[SlackMessagesActivity], 5)
+	}
+	if activityCounts[SlackThreadsAnsweredActivity] != 2 {
+		t.Errorf("slack threads answered: got %d, want %d", activityCounts[SlackThreadsAnsweredActivity], 2)
+	}
+	if !strings.Contains(report.Summary(), "#virtualization: messages: 4, threads answered: 1") {
+		t.Errorf("summary does not contain slack channel: %s", report.Summary())
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SlashCommand is a Prow command that is issued by commenting on an issue
+// or a pull request, see https://prow.ci.kubevirt.io/command-help
+type SlashCommand string
+
+const (
+	LGTMCommand    SlashCommand = "lgtm"
+	ApproveCommand SlashCommand = "approve"
+	HoldCommand    SlashCommand = "hold"
+	RetestCommand  SlashCommand = "retest"
+)
+
+// SlashCommandTypes holds the counted commands in the order of presentation.
+var SlashCommandTypes = []SlashCommand{
+	LGTMCommand,
+	ApproveCommand,
+	HoldCommand,
+	RetestCommand,
+}
+
+// slashCommandActivities maps the commands to the activity types that they
+// are counted as
+var slashCommandActivities = map[SlashCommand]ActivityType{
+	LGTMCommand:    LGTMCommandsActivity,
+	ApproveCommand: ApproveCommandsActivity,
+	HoldCommand:    HoldCommandsActivity,
+	RetestCommand:  RetestCommandsActivity,
+}
+
+// slashCommandMatcher matches commands at the start of a line of a comment,
+// where the first argument (i.e. `cancel`) is captured.
+var slashCommandMatcher = regexp.MustCompile(`(?m)^/(lgtm|approve|hold|retest)(?:[ \t]+(\S+))?[ \t]*\r?$`)
+
+// SlashCommands holds the URLs of the comments per command that a user
+// issued. Cancelling commands (i.e. `/lgtm cancel`) are not counted.
+type SlashCommands map[SlashCommand][]string
+
+// parseSlashCommands returns the commands contained in the comment body,
+// each command at most once.
+func parseSlashCommands(body string) []SlashCommand {
+	var commands []SlashCommand
+	found := make(map[SlashCommand]struct{})
+	for _, match := range slashCommandMatcher.FindAllStringSubmatch(body, -1) {
+		command := SlashCommand(match[1])
+		if strings.EqualFold(match[2], "cancel") {
+			continue
+		}
+		if _, exists := found[command]; exists {
+			continue
+		}
+		found[command] = struct{}{}
+		commands = append(commands, command)
+	}
+	return commands
+}
+
+func (s SlashCommands) add(body, url string) {
+	for _, command := range parseSlashCommands(body) {
+		s[command] = append(s[command], url)
+	}
+}
+
+func (s SlashCommands) merge(other SlashCommands) {
+	for command, urls := range other {
+		s[command] = append(s[command], urls...)
+	}
+}
+
+func (s SlashCommands) activityCounts() ActivityCounts {
+	activityCounts := ActivityCounts{}
+	for command, urls := range s {
+		activityCounts[slashCommandActivities[command]] += len(urls)
+	}
+	return activityCounts
+}
+
+func (s SlashCommands) summary() string {
+	var summary strings.Builder
+	summary.WriteString(`
+    prow commands:
+`)
+	for _, command := range SlashCommandTypes {
+		summary.WriteString(fmt.Sprintf("        %-18s %d\n", fmt.Sprintf("/%s:", command), len(s[command])))
+	}
+	return summary.String()
+}
+
+// slashCommands collects the commands from the comments that userName
+// authored within the window.
+func (u *UserContributions) slashCommands(userName string, window ReportWindow) SlashCommands {
+	slashCommands := SlashCommands{}
+	for _, node := range u.IssuesCommented.Nodes {
+		for _, comment := range node.Issue.Comments.Nodes {
+			if strings.EqualFold(comment.Author.Login, userName) && window.contains(comment.CreatedAt) {
+				slashCommands.add(comment.Body, comment.URL)
+			}
+		}
+	}
+	for _, node := range u.PullRequestsCommented.Nodes {
+		for _, comment := range node.PullRequest.Comments.Nodes {
+			if strings.EqualFold(comment.Author.Login, userName) && window.contains(comment.CreatedAt) {
+				slashCommands.add(comment.Body, comment.URL)
+			}
+		}
+	}
+	return slashCommands
+}
+
+func (u *UserContributionReportForScopes) slashCommands() SlashCommands {
+	slashCommands := SlashCommands{}
+	for _, report := range u.RepositoryReports {
+		slashCommands.merge(report.slashCommands(report.UserName, report.Window))
+	}
+	for _, report := range u.OrganizationReports {
+		slashCommands.merge(report.slashCommands(report.UserName, report.Window))
+	}
+	return slashCommands
+}
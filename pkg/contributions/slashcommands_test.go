@@ -0,0 +1,100 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSlashCommands(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		expected []SlashCommand
+	}{
+		{
+			name:     "single command",
+			body:     "/lgtm",
+			expected: []SlashCommand{LGTMCommand},
+		},
+		{
+			name:     "multiple commands with text",
+			body:     "thanks!\r\n/lgtm\r\n/approve no-issue\r\n",
+			expected: []SlashCommand{LGTMCommand, ApproveCommand},
+		},
+		{
+			name:     "repeated command is counted once",
+			body:     "/retest\n/retest",
+			expected: []SlashCommand{RetestCommand},
+		},
+		{
+			name: "cancel is not counted",
+			body: "/hold cancel\n/lgtm cancel",
+		},
+		{
+			name: "command within text is not counted",
+			body: "please /approve this",
+		},
+		{
+			name: "other commands are not counted",
+			body: "/lgtmx\n/test all",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			commands := parseSlashCommands(testCase.body)
+			if !reflect.DeepEqual(commands, testCase.expected) {
+				t.Errorf("got %v, want %v", commands, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestReportSlashCommands(t *testing.T) {
+	generator := newTestGenerator(t, "repository", ContributionReportGeneratorOptions{
+		Org:   "kubevirt",
+		Repo:  "community",
+		Since: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	report, err := generator.GenerateReport(context.Background(), "testuser")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repoReport := report.(*UserContributionReportForRepository)
+
+	// the /hold has been issued by another user
+	expected := SlashCommands{
+		LGTMCommand:    {"https://github.com/kubevirt/community/pull/5#issuecomment-3"},
+		ApproveCommand: {"https://github.com/kubevirt/community/pull/5#issuecomment-3"},
+	}
+	slashCommands := repoReport.slashCommands(repoReport.UserName, repoReport.Window)
+	if !reflect.DeepEqual(slashCommands, expected) {
+		t.Errorf("got %v, want %v", slashCommands, expected)
+	}
+	activityCounts := report.ActivityCounts()
+	if activityCounts[LGTMCommandsActivity] != 1 || activityCounts[ApproveCommandsActivity] != 1 || activityCounts[HoldCommandsActivity] != 0 {
+		t.Errorf("unexpected activity counts: %v", activityCounts)
+	}
+}
@@ -0,0 +1,213 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timelineMonthFormat is the format of TimelineMonth.Month
+const timelineMonthFormat = "2006-01"
+
+// TimelineMonth holds the number of contributions of a user within a
+// calendar month (UTC).
+type TimelineMonth struct {
+	Month        string `yaml:"month"`
+	Issues       int    `yaml:"issues"`
+	PullRequests int    `yaml:"pullRequests"`
+	Reviews      int    `yaml:"reviews"`
+	Comments     int    `yaml:"comments"`
+	Commits      int    `yaml:"commits"`
+	Discussions  int    `yaml:"discussions"`
+	Releases     int    `yaml:"releases"`
+}
+
+func (m TimelineMonth) total() int {
+	return m.Issues + m.PullRequests + m.Reviews + m.Comments + m.Commits + m.Discussions + m.Releases
+}
+
+// Timeline is the histogram of the contributions of a user per month of
+// the report window, where months without contributions are included.
+// Only the contributions that have been fetched are counted, i.e. if the
+// number of pages has been limited the timeline is incomplete.
+type Timeline struct {
+	Months       []TimelineMonth `yaml:"months"`
+	LastActivity *time.Time      `yaml:"lastActivity,omitempty"`
+
+	// window is the report window that contributions are counted within
+	window ReportWindow
+}
+
+func newTimeline(window ReportWindow) *Timeline {
+	timeline := &Timeline{window: window}
+	since := window.Since.UTC()
+	until := window.Until.UTC()
+	for month := time.Date(since.Year(), since.Month(), 1, 0, 0, 0, 0, time.UTC); month.Before(until); month = month.AddDate(0, 1, 0) {
+		timeline.Months = append(timeline.Months, TimelineMonth{Month: month.Format(timelineMonthFormat)})
+	}
+	return timeline
+}
+
+// add records a contribution at the given time, where counter selects the
+// counter of the month to increment. Contributions without a time or
+// outside the report window are ignored, i.e. earlier reviews of a pull
+// request that has been updated within the window.
+func (t *Timeline) add(at time.Time, counter func(*TimelineMonth) *int) {
+	if at.IsZero() || !t.window.contains(at) {
+		return
+	}
+	month := at.UTC().Format(timelineMonthFormat)
+	for i := range t.Months {
+		if t.Months[i].Month != month {
+			continue
+		}
+		*counter(&t.Months[i])++
+		if t.LastActivity == nil || at.After(*t.LastActivity) {
+			lastActivity := at
+			t.LastActivity = &lastActivity
+		}
+		return
+	}
+}
+
+// merge adds the counts of other to the timeline.
+func (t *Timeline) merge(other *Timeline) {
+	for _, otherMonth := range other.Months {
+		for i := range t.Months {
+			if t.Months[i].Month != otherMonth.Month {
+				continue
+			}
+			t.Months[i].Issues += otherMonth.Issues
+			t.Months[i].PullRequests += otherMonth.PullRequests
+			t.Months[i].Reviews += otherMonth.Reviews
+			t.Months[i].Comments += otherMonth.Comments
+			t.Months[i].Commits += otherMonth.Commits
+			t.Months[i].Discussions += otherMonth.Discussions
+			t.Months[i].Releases += otherMonth.Releases
+		}
+	}
+	if other.LastActivity != nil && (t.LastActivity == nil || other.LastActivity.After(*t.LastActivity)) {
+		t.LastActivity = other.LastActivity
+	}
+}
+
+func (t *Timeline) summary() string {
+	lastActivity := "none"
+	if t.LastActivity != nil {
+		lastActivity = t.LastActivity.Format(time.DateOnly)
+	}
+	var summary strings.Builder
+	summary.WriteString(fmt.Sprintf(`
+    last activity: %s
+
+`, lastActivity))
+	summary.WriteString(fmt.Sprintf("    %-12s %6s %6s %8s %8s %8s %11s %8s\n", "timeline:", "issues", "prs", "reviews", "comments", "commits", "discussions", "releases"))
+	for _, month := range t.Months {
+		line := fmt.Sprintf("        %s: %6d %6d %8d %8d %8d %11d %8d  %s",
+			month.Month, month.Issues, month.PullRequests, month.Reviews, month.Comments, month.Commits, month.Discussions, month.Releases,
+			strings.Repeat("#", min(month.total(), 20)))
+		summary.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	return summary.String()
+}
+
+func issuesCounter(m *TimelineMonth) *int       { return &m.Issues }
+func pullRequestsCounter(m *TimelineMonth) *int { return &m.PullRequests }
+func reviewsCounter(m *TimelineMonth) *int      { return &m.Reviews }
+func commentsCounter(m *TimelineMonth) *int     { return &m.Comments }
+func commitsCounter(m *TimelineMonth) *int      { return &m.Commits }
+func discussionsCounter(m *TimelineMonth) *int  { return &m.Discussions }
+func releasesCounter(m *TimelineMonth) *int     { return &m.Releases }
+
+// addTo adds the contributions of userName to the timeline, where only
+// the comments that the user authored are counted. Discussions are counted
+// when the user started them or wrote the accepted answer.
+func (u *UserContributions) addTo(timeline *Timeline, userName string) {
+	for _, node := range u.IssuesCreated.Nodes {
+		timeline.add(node.Issue.CreatedAt, issuesCounter)
+	}
+	for _, node := range u.PullRequestsCreated.Nodes {
+		timeline.add(node.PullRequest.CreatedAt, pullRequestsCounter)
+	}
+	for _, node := range u.PullRequestsReviewed.Nodes {
+		for _, review := range node.PullRequestReview.Reviews.Nodes {
+			timeline.add(review.SubmittedAt, reviewsCounter)
+		}
+	}
+	for _, node := range u.IssuesCommented.Nodes {
+		for _, comment := range node.Issue.Comments.Nodes {
+			if strings.EqualFold(comment.Author.Login, userName) {
+				timeline.add(comment.CreatedAt, commentsCounter)
+			}
+		}
+	}
+	for _, node := range u.PullRequestsCommented.Nodes {
+		for _, comment := range node.PullRequest.Comments.Nodes {
+			if strings.EqualFold(comment.Author.Login, userName) {
+				timeline.add(comment.CreatedAt, commentsCounter)
+			}
+		}
+	}
+	for _, node := range u.DiscussionsCreated.Nodes {
+		timeline.add(node.Discussion.CreatedAt, discussionsCounter)
+	}
+	for _, node := range u.DiscussionsCommented.Nodes {
+		answer := node.Discussion.Answer
+		if answer != nil && strings.EqualFold(answer.Author.Login, userName) {
+			timeline.add(answer.CreatedAt, discussionsCounter)
+		}
+	}
+	for _, node := range u.ReleasesCreated.Nodes {
+		timeline.add(node.PublishedAt, releasesCounter)
+	}
+}
+
+func (u *UserContributionReportForRepository) timeline() *Timeline {
+	timeline := newTimeline(u.Window)
+	u.UserContributions.addTo(timeline, u.UserName)
+	for _, node := range u.CommitsByUser.DefaultBranchRef.Target.Fragment.History.Nodes {
+		timeline.add(node.CommittedDate, commitsCounter)
+	}
+	return timeline
+}
+
+func (u *UserContributionReportForOrganization) timeline() *Timeline {
+	timeline := newTimeline(u.Window)
+	u.UserContributions.addTo(timeline, u.UserName)
+	for _, repository := range u.CommitsByUserInOrg.Repositories.Nodes {
+		for _, node := range repository.DefaultBranchRef.Target.Fragment.History.Nodes {
+			timeline.add(node.CommittedDate, commitsCounter)
+		}
+	}
+	return timeline
+}
+
+func (u *UserContributionReportForScopes) timeline() *Timeline {
+	timeline := newTimeline(u.Window)
+	for _, report := range u.RepositoryReports {
+		timeline.merge(report.timeline())
+	}
+	for _, report := range u.OrganizationReports {
+		timeline.merge(report.timeline())
+	}
+	return timeline
+}
@@ -0,0 +1,125 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReportTimeline(t *testing.T) {
+	generator := newTestGenerator(t, "repository", ContributionReportGeneratorOptions{
+		Org:   "kubevirt",
+		Repo:  "community",
+		Since: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	report, err := generator.GenerateReport(context.Background(), "testuser")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	timeline := report.(*UserContributionReportForRepository).timeline()
+
+	expectedMonths := []TimelineMonth{
+		{Month: "2024-10", PullRequests: 1, Reviews: 2, Comments: 1, Commits: 2},
+		{Month: "2024-11", Issues: 2, Comments: 1, Discussions: 2, Releases: 1},
+		{Month: "2024-12"},
+	}
+	if !reflect.DeepEqual(timeline.Months, expectedMonths) {
+		t.Errorf("months: got %+v, want %+v", timeline.Months, expectedMonths)
+	}
+	expectedLastActivity := time.Date(2024, 11, 28, 10, 39, 57, 0, time.UTC)
+	if timeline.LastActivity == nil || !timeline.LastActivity.Equal(expectedLastActivity) {
+		t.Errorf("last activity: got %v, want %v", timeline.LastActivity, expectedLastActivity)
+	}
+	if !strings.Contains(report.Summary(), "last activity: 2024-11-28") {
+		t.Errorf("summary does not contain last activity: %s", report.Summary())
+	}
+}
+
+func TestNewTimeline(t *testing.T) {
+	testCases := []struct {
+		name           string
+		window         ReportWindow
+		expectedMonths []string
+	}{
+		{
+			name: "whole months",
+			window: ReportWindow{
+				Since: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+				Until: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			expectedMonths: []string{"2024-11", "2024-12"},
+		},
+		{
+			name: "partial months",
+			window: ReportWindow{
+				Since: time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC),
+				Until: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+			},
+			expectedMonths: []string{"2024-11", "2024-12", "2025-01"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var months []string
+			for _, month := range newTimeline(testCase.window).Months {
+				months = append(months, month.Month)
+			}
+			if !reflect.DeepEqual(months, testCase.expectedMonths) {
+				t.Errorf("got %v, want %v", months, testCase.expectedMonths)
+			}
+		})
+	}
+}
+
+func TestTimelineAddWithinWindow(t *testing.T) {
+	timeline := newTimeline(ReportWindow{
+		Since: time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	reviewsCounter := func(month *TimelineMonth) *int { return &month.Reviews }
+	for _, at := range []time.Time{
+		{},
+		time.Date(2024, 11, 14, 23, 59, 59, 0, time.UTC),
+		time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 1, 23, 59, 59, 0, time.UTC),
+		time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+	} {
+		timeline.add(at, reviewsCounter)
+	}
+
+	expectedMonths := []TimelineMonth{
+		{Month: "2024-11", Reviews: 1},
+		{Month: "2024-12"},
+		{Month: "2025-01", Reviews: 1},
+	}
+	if !reflect.DeepEqual(timeline.Months, expectedMonths) {
+		t.Errorf("months: got %+v, want %+v", timeline.Months, expectedMonths)
+	}
+	expectedLastActivity := time.Date(2025, 1, 1, 23, 59, 59, 0, time.UTC)
+	if timeline.LastActivity == nil || !timeline.LastActivity.Equal(expectedLastActivity) {
+		t.Errorf("last activity: got %v, want %v", timeline.LastActivity, expectedLastActivity)
+	}
+}
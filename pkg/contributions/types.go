@@ -21,29 +21,60 @@ package contributions
 
 import (
 	"fmt"
-	"path/filepath"
+	"sort"
 	"time"
 )
 
 type ContributionReport interface {
 	Summary() string
-	ReportFileName(userName string) string
+	ReportFileName(userName string, format Format) string
 	HasContributions() bool
-	WriteToFile(dir, userName string) (string, error)
+	ActivityCounts() ActivityCounts
+	Identity() UserIdentity
+	WriteToFile(dir, userName string, format Format) (string, error)
 }
 
-type UserContributionReportForRepository struct {
+// UserContributions holds the search results that are common to all
+// contribution reports, regardless of whether they target an organization
+// or a single repository.
+type UserContributions struct {
 	IssuesCreated         IssuesCreated         `yaml:"issuesCreated"`
 	IssuesCommented       IssuesCommented       `yaml:"issuesCommented"`
 	PullRequestsCreated   PullRequestsCreated   `yaml:"pullRequestsCreated"`
 	PullRequestsReviewed  PullRequestsReviewed  `yaml:"pullRequestsReviewed"`
 	PullRequestsCommented PullRequestsCommented `yaml:"pullRequestsCommented"`
-	CommitsByUser         CommitsByUser         `yaml:"commitsByUser"`
+	DiscussionsCreated    DiscussionsCreated    `yaml:"discussionsCreated"`
+	DiscussionsCommented  DiscussionsCommented  `yaml:"discussionsCommented"`
+	ReleasesCreated       ReleasesCreated       `yaml:"releasesCreated"`
+}
+
+func (u *UserContributions) hasContributions() bool {
+	return u.IssuesCreated.IssueCount > 0 ||
+		u.IssuesCommented.IssueCount > 0 ||
+		u.PullRequestsReviewed.IssueCount > 0 ||
+		u.PullRequestsCreated.IssueCount > 0 ||
+		u.PullRequestsCommented.IssueCount > 0 ||
+		u.DiscussionsCreated.IssueCount > 0 ||
+		u.DiscussionsCommented.IssueCount > 0 ||
+		u.ReleasesCreated.TotalCount > 0
+}
+
+type UserContributionReportForRepository struct {
+	UserContributions     `yaml:",inline"`
+	ExternalContributions `yaml:",inline"`
+	CommitsByUser         CommitsByUser `yaml:"commitsByUser"`
 	Org                   string
 	Repo                  string
 	UserName              string
 	UserID                string
-	StartFrom             time.Time
+	UserDatabaseID        int64
+	Window                ReportWindow `yaml:"window"`
+
+	// Timeline, ReviewDetails and SlashCommands are derived from the
+	// contributions when writing the report
+	Timeline      *Timeline      `yaml:"timeline,omitempty"`
+	ReviewDetails *ReviewDetails `yaml:"reviewDetails,omitempty"`
+	SlashCommands SlashCommands  `yaml:"slashCommands,omitempty"`
 }
 
 func (u *UserContributionReportForRepository) Summary() string {
@@ -51,6 +82,7 @@ func (u *UserContributionReportForRepository) Summary() string {
     user:          %s
     repository:    %s/%s
     since:         %s
+    until:         %s
 
     issues
         created:   %d
@@ -60,56 +92,70 @@ func (u *UserContributionReportForRepository) Summary() string {
         created:   %d
         commented: %d
     commits:       %d
-`, u.UserName, u.Org, u.Repo, u.StartFrom.Format(time.DateTime),
+`, u.UserName, u.Org, u.Repo, u.Window.Since.Format(time.DateTime), u.Window.Until.Format(time.DateTime),
 		u.IssuesCreated.IssueCount,
 		u.IssuesCommented.IssueCount,
 		u.PullRequestsReviewed.IssueCount,
 		u.PullRequestsCreated.IssueCount,
 		u.PullRequestsCommented.IssueCount,
 		u.CommitsByUser.DefaultBranchRef.Target.Fragment.History.TotalCount,
-	)
+	) + discussionsAndReleasesSummary(u.ActivityCounts()) + u.ExternalContributions.summary() +
+		u.reviewDetails(u.Window).summary() + u.slashCommands(u.UserName, u.Window).summary() + u.timeline().summary()
 }
 
-func (u *UserContributionReportForRepository) ReportFileName(userName string) string {
-	return fmt.Sprintf("user-activity-%s-%s_%s-*.yaml", userName, u.Org, u.Repo)
+func (u *UserContributionReportForRepository) ReportFileName(userName string, format Format) string {
+	return activityFileName(userName, u.Org+"_"+u.Repo, u.Window, format)
+}
+
+func (u *UserContributionReportForRepository) Identity() UserIdentity {
+	return UserIdentity{Login: u.UserName, ID: u.UserID, DatabaseID: u.UserDatabaseID}
 }
 
 func (u *UserContributionReportForRepository) HasContributions() bool {
-	return u.IssuesCreated.IssueCount > 0 ||
-		u.IssuesCommented.IssueCount > 0 ||
-		u.PullRequestsReviewed.IssueCount > 0 ||
-		u.PullRequestsCreated.IssueCount > 0 ||
-		u.PullRequestsCommented.IssueCount > 0 ||
-		u.CommitsByUser.DefaultBranchRef.Target.Fragment.History.TotalCount > 0
+	return u.UserContributions.hasContributions() ||
+		u.CommitsByUser.DefaultBranchRef.Target.Fragment.History.TotalCount > 0 ||
+		u.ExternalContributions.hasContributions()
 }
 
-func (u *UserContributionReportForRepository) WriteToFile(dir string, userName string) (string, error) {
-	logFileName := u.ReportFileName(userName)
-	err := writeActivityToFile(u, dir, logFileName)
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(dir, logFileName), nil
+func (u *UserContributionReportForRepository) ActivityCounts() ActivityCounts {
+	activityCounts := u.UserContributions.activityCounts(u.UserName, u.Window)
+	activityCounts[CommitsActivity] = u.CommitsByUser.DefaultBranchRef.Target.Fragment.History.TotalCount
+	activityCounts.Add(u.slashCommands(u.UserName, u.Window).activityCounts())
+	activityCounts.Add(u.ExternalContributions.activityCounts())
+	return activityCounts
+}
+
+func (u *UserContributionReportForRepository) WriteToFile(dir string, userName string, format Format) (string, error) {
+	u.Timeline = u.timeline()
+	u.ReviewDetails = u.reviewDetails(u.Window)
+	u.SlashCommands = u.slashCommands(u.UserName, u.Window)
+	return writeActivityToFile(u, dir, u.ReportFileName(userName, format), format)
 }
 
 type UserContributionReportForOrganization struct {
-	IssuesCreated         IssuesCreated         `yaml:"issuesCreated"`
-	IssuesCommented       IssuesCommented       `yaml:"issuesCommented"`
-	PullRequestsCreated   PullRequestsCreated   `yaml:"pullRequestsCreated"`
-	PullRequestsReviewed  PullRequestsReviewed  `yaml:"pullRequestsReviewed"`
-	PullRequestsCommented PullRequestsCommented `yaml:"pullRequestsCommented"`
-	CommitsByUserInOrg    CommitsByUserInOrg    `yaml:"commitsByUserInOrg"`
+	UserContributions     `yaml:",inline"`
+	ExternalContributions `yaml:",inline"`
+	CommitsByUserInOrg    CommitsByUserInOrg `yaml:"commitsByUserInOrg"`
 	Org                   string
 	UserName              string
 	UserID                string
-	StartFrom             time.Time
+	UserDatabaseID        int64
+	Window                ReportWindow `yaml:"window"`
+	IncludeArchived       bool         `yaml:"includeArchived"`
+
+	// Timeline, ReviewDetails and SlashCommands are derived from the
+	// contributions when writing the report
+	Timeline      *Timeline      `yaml:"timeline,omitempty"`
+	ReviewDetails *ReviewDetails `yaml:"reviewDetails,omitempty"`
+	SlashCommands SlashCommands  `yaml:"slashCommands,omitempty"`
 }
 
 func (u *UserContributionReportForOrganization) Summary() string {
-	return fmt.Sprintf(`activity log:
+	summary := fmt.Sprintf(`activity log:
     user:          %s
     org:           %s
     since:         %s
+    until:         %s
 
     issues
         created:   %d
@@ -119,7 +165,7 @@ func (u *UserContributionReportForOrganization) Summary() string {
         created:   %d
         commented: %d
     commits:       %d
-`, u.UserName, u.Org, u.StartFrom.Format(time.DateTime),
+`, u.UserName, u.Org, u.Window.Since.Format(time.DateTime), u.Window.Until.Format(time.DateTime),
 		u.IssuesCreated.IssueCount,
 		u.IssuesCommented.IssueCount,
 		u.PullRequestsReviewed.IssueCount,
@@ -127,6 +173,27 @@ func (u *UserContributionReportForOrganization) Summary() string {
 		u.PullRequestsCommented.IssueCount,
 		u.totalCommitCount(),
 	)
+	for _, node := range u.CommitsByUserInOrg.Repositories.Nodes {
+		summary += fmt.Sprintf(`        %s: %d
+`, node.Name, node.DefaultBranchRef.Target.Fragment.History.TotalCount)
+	}
+	summary += discussionsAndReleasesSummary(u.ActivityCounts()) + u.ExternalContributions.summary()
+	return summary + u.reviewDetails(u.Window).summary() + u.slashCommands(u.UserName, u.Window).summary() + u.timeline().summary()
+}
+
+// discussionsAndReleasesSummary returns the part of the summary that lists
+// the discussions and releases of the user.
+func discussionsAndReleasesSummary(activityCounts ActivityCounts) string {
+	return fmt.Sprintf(`    discussions:
+        created:   %d
+        answered:  %d
+        commented: %d
+    releases:      %d
+`, activityCounts[DiscussionsCreatedActivity],
+		activityCounts[DiscussionsAnsweredActivity],
+		activityCounts[DiscussionsCommentedActivity],
+		activityCounts[ReleasesCreatedActivity],
+	)
 }
 
 func (u *UserContributionReportForOrganization) totalCommitCount() int {
@@ -137,26 +204,224 @@ func (u *UserContributionReportForOrganization) totalCommitCount() int {
 	return totalCommitCount
 }
 
-func (u *UserContributionReportForOrganization) ReportFileName(userName string) string {
-	return fmt.Sprintf("user-activity-%s-%s-*.yaml", userName, u.Org)
+func (u *UserContributionReportForOrganization) ReportFileName(userName string, format Format) string {
+	return activityFileName(userName, u.Org, u.Window, format)
+}
+
+func (u *UserContributionReportForOrganization) Identity() UserIdentity {
+	return UserIdentity{Login: u.UserName, ID: u.UserID, DatabaseID: u.UserDatabaseID}
 }
 
 func (u *UserContributionReportForOrganization) HasContributions() bool {
-	return u.IssuesCreated.IssueCount > 0 ||
-		u.IssuesCommented.IssueCount > 0 ||
-		u.PullRequestsReviewed.IssueCount > 0 ||
-		u.PullRequestsCreated.IssueCount > 0 ||
-		u.PullRequestsCommented.IssueCount > 0 ||
-		u.totalCommitCount() > 0
+	return u.UserContributions.hasContributions() ||
+		u.totalCommitCount() > 0 ||
+		u.ExternalContributions.hasContributions()
+}
+
+func (u *UserContributionReportForOrganization) ActivityCounts() ActivityCounts {
+	activityCounts := u.UserContributions.activityCounts(u.UserName, u.Window)
+	activityCounts[CommitsActivity] = u.totalCommitCount()
+	activityCounts.Add(u.slashCommands(u.UserName, u.Window).activityCounts())
+	activityCounts.Add(u.ExternalContributions.activityCounts())
+	return activityCounts
+}
+
+func (u *UserContributionReportForOrganization) WriteToFile(dir, userName string, format Format) (string, error) {
+	u.Timeline = u.timeline()
+	u.ReviewDetails = u.reviewDetails(u.Window)
+	u.SlashCommands = u.slashCommands(u.UserName, u.Window)
+	return writeActivityToFile(u, dir, u.ReportFileName(userName, format), format)
+}
+
+// UserContributionReportForScopes aggregates the contribution reports of a
+// user over a set of repositories and organizations, i.e. the repositories
+// of a SIG or several related organizations.
+type UserContributionReportForScopes struct {
+	Name                string                                   `yaml:"name"`
+	UserName            string                                   `yaml:"userName"`
+	Window              ReportWindow                             `yaml:"window"`
+	Scopes              []Scope                                  `yaml:"scopes,omitempty"`
+	RepositoryReports   []*UserContributionReportForRepository   `yaml:"repositoryReports,omitempty"`
+	OrganizationReports []*UserContributionReportForOrganization `yaml:"organizationReports,omitempty"`
+
+	ExternalContributions `yaml:",inline"`
+
+	// Timeline, ReviewDetails and SlashCommands are derived from the
+	// contributions when writing the report
+	Timeline      *Timeline      `yaml:"timeline,omitempty"`
+	ReviewDetails *ReviewDetails `yaml:"reviewDetails,omitempty"`
+	SlashCommands SlashCommands  `yaml:"slashCommands,omitempty"`
+
+	// PerOrg holds the activity counts summed up per org, it is derived
+	// from the reports when writing the report
+	PerOrg map[string]ActivityCounts `yaml:"perOrg,omitempty"`
+}
+
+func (u *UserContributionReportForScopes) add(scope Scope, report ContributionReport) {
+	u.Scopes = append(u.Scopes, scope)
+	switch r := report.(type) {
+	case *UserContributionReportForRepository:
+		u.RepositoryReports = append(u.RepositoryReports, r)
+	case *UserContributionReportForOrganization:
+		u.OrganizationReports = append(u.OrganizationReports, r)
+	}
 }
 
-func (u *UserContributionReportForOrganization) WriteToFile(dir, userName string) (string, error) {
-	logFileName := u.ReportFileName(userName)
-	err := writeActivityToFile(u, dir, logFileName)
-	if err != nil {
-		return "", err
+func (u *UserContributionReportForScopes) reports() []scopeReport {
+	var reports []scopeReport
+	for _, report := range u.OrganizationReports {
+		reports = append(reports, scopeReport{Scope{Org: report.Org}, report})
+	}
+	for _, report := range u.RepositoryReports {
+		reports = append(reports, scopeReport{Scope{Org: report.Org, Repo: report.Repo}, report})
 	}
-	return filepath.Join(dir, logFileName), nil
+	// keep the order of the scopes, where reports of scopes that are not
+	// listed come last
+	scopeIndex := func(scope Scope) int {
+		for i, listedScope := range u.Scopes {
+			if listedScope == scope {
+				return i
+			}
+		}
+		return len(u.Scopes)
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return scopeIndex(reports[i].scope) < scopeIndex(reports[j].scope)
+	})
+	return reports
+}
+
+type scopeReport struct {
+	scope Scope
+	ContributionReport
+}
+
+// orgActivityCounts sums up the activity counts of the scopes per org,
+// where orgs are returned in the order of the scopes.
+func (u *UserContributionReportForScopes) orgActivityCounts() ([]string, map[string]ActivityCounts) {
+	var orgs []string
+	orgActivityCounts := make(map[string]ActivityCounts)
+	for _, report := range u.reports() {
+		activityCounts, exists := orgActivityCounts[report.scope.Org]
+		if !exists {
+			orgs = append(orgs, report.scope.Org)
+			activityCounts = ActivityCounts{}
+			orgActivityCounts[report.scope.Org] = activityCounts
+		}
+		activityCounts.Add(report.ActivityCounts())
+	}
+	return orgs, orgActivityCounts
+}
+
+func scopeActivityCountsSummary(name string, activityCounts ActivityCounts) string {
+	return fmt.Sprintf(`        %s:
+            issues created/commented: %d/%d, pull requests reviewed/created/commented: %d/%d/%d, commits: %d
+`, name,
+		activityCounts[IssuesCreatedActivity],
+		activityCounts[IssuesCommentedActivity],
+		activityCounts[PullRequestsReviewedActivity],
+		activityCounts[PullRequestsCreatedActivity],
+		activityCounts[PullRequestsCommentedActivity],
+		activityCounts[CommitsActivity],
+	)
+}
+
+func (u *UserContributionReportForScopes) Summary() string {
+	activityCounts := u.ActivityCounts()
+	summary := fmt.Sprintf(`activity log:
+    user:          %s
+    scopes:        %s
+    since:         %s
+    until:         %s
+
+    issues
+        created:   %d
+        commented: %d
+    pull requests:
+        reviewed:  %d
+        created:   %d
+        commented: %d
+    commits:       %d
+`, u.UserName, u.Name, u.Window.Since.Format(time.DateTime), u.Window.Until.Format(time.DateTime),
+		activityCounts[IssuesCreatedActivity],
+		activityCounts[IssuesCommentedActivity],
+		activityCounts[PullRequestsReviewedActivity],
+		activityCounts[PullRequestsCreatedActivity],
+		activityCounts[PullRequestsCommentedActivity],
+		activityCounts[CommitsActivity],
+	)
+	summary += discussionsAndReleasesSummary(activityCounts) + u.ExternalContributions.summary()
+	orgs, orgActivityCounts := u.orgActivityCounts()
+	summary += `
+    per org:
+`
+	for _, org := range orgs {
+		summary += scopeActivityCountsSummary(org, orgActivityCounts[org])
+	}
+	summary += `
+    per scope:
+`
+	for _, report := range u.reports() {
+		summary += scopeActivityCountsSummary(report.scope.String(), report.ActivityCounts())
+	}
+	return summary + u.reviewDetails().summary() + u.slashCommands().summary() + u.timeline().summary()
+}
+
+func (u *UserContributionReportForScopes) ReportFileName(userName string, format Format) string {
+	return activityFileName(userName, u.Name, u.Window, format)
+}
+
+// Identity returns the identity of the user from the first report that
+// carries it, since all reports are generated for the same user.
+func (u *UserContributionReportForScopes) Identity() UserIdentity {
+	for _, report := range u.reports() {
+		if identity := report.Identity(); identity.ID != "" {
+			return identity
+		}
+	}
+	return UserIdentity{Login: u.UserName}
+}
+
+func (u *UserContributionReportForScopes) HasContributions() bool {
+	for _, report := range u.reports() {
+		if report.HasContributions() {
+			return true
+		}
+	}
+	return u.ExternalContributions.hasContributions()
+}
+
+func (u *UserContributionReportForScopes) ActivityCounts() ActivityCounts {
+	activityCounts := ActivityCounts{}
+	for _, report := range u.reports() {
+		activityCounts.Add(report.ActivityCounts())
+	}
+	activityCounts.Add(u.ExternalContributions.activityCounts())
+	return activityCounts
+}
+
+func (u *UserContributionReportForScopes) WriteToFile(dir, userName string, format Format) (string, error) {
+	u.Timeline = u.timeline()
+	u.ReviewDetails = u.reviewDetails()
+	u.SlashCommands = u.slashCommands()
+	_, u.PerOrg = u.orgActivityCounts()
+	for _, report := range u.RepositoryReports {
+		report.Timeline = report.timeline()
+		report.ReviewDetails = report.reviewDetails(report.Window)
+		report.SlashCommands = report.slashCommands(report.UserName, report.Window)
+	}
+	for _, report := range u.OrganizationReports {
+		report.Timeline = report.timeline()
+		report.ReviewDetails = report.reviewDetails(report.Window)
+		report.SlashCommands = report.slashCommands(report.UserName, report.Window)
+	}
+	return writeActivityToFile(u, dir, u.ReportFileName(userName, format), format)
+}
+
+// PageInfo is used to walk the pages of a GraphQL connection.
+type PageInfo struct {
+	HasNextPage bool   `yaml:"hasNextPage"`
+	EndCursor   string `yaml:"endCursor"`
 }
 
 type Repository struct {
@@ -180,6 +445,7 @@ type IssuesCreatedNodeItem struct {
 
 type IssuesCreated struct {
 	IssueCount int                     `yaml:"issueCount"`
+	PageInfo   PageInfo                `yaml:"pageInfo"`
 	Nodes      []IssuesCreatedNodeItem `yaml:"nodes"`
 }
 
@@ -190,19 +456,23 @@ type CommentItem struct {
 	Author    CommentAuthor `yaml:"author"`
 	CreatedAt time.Time     `yaml:"createdAt"`
 	URL       string        `yaml:"URL"`
+	Body      string        `yaml:"body"`
 }
 
 type Comments struct {
-	Nodes []CommentItem `yaml:"nodes"`
+	PageInfo PageInfo      `yaml:"pageInfo"`
+	Nodes    []CommentItem `yaml:"nodes"`
 }
 
 type IssueWithCommentFragment struct {
+	ID         string     `yaml:"id"`
 	Number     int        `yaml:"number"`
 	Title      string     `yaml:"title"`
 	URL        string     `yaml:"URL"`
+	CreatedAt  time.Time  `yaml:"createdAt"`
 	Repository Repository `yaml:"repository"`
 	Author     Author     `yaml:"author"`
-	Comments   Comments   `graphql:"comments(first:100, orderBy: {field: UPDATED_AT, direction: ASC} )" yaml:"comments"`
+	Comments   Comments   `graphql:"comments(first: 100, orderBy: {field: UPDATED_AT, direction: ASC})" yaml:"comments"`
 }
 
 type IssuesCommentedNodeItem struct {
@@ -211,6 +481,7 @@ type IssuesCommentedNodeItem struct {
 
 type IssuesCommented struct {
 	IssueCount int                       `yaml:"issueCount"`
+	PageInfo   PageInfo                  `yaml:"pageInfo"`
 	Nodes      []IssuesCommentedNodeItem `yaml:"nodes"`
 }
 
@@ -232,25 +503,37 @@ type PullRequestNodeItem struct {
 
 type PullRequestsCreated struct {
 	IssueCount int                   `yaml:"issueCount"`
+	PageInfo   PageInfo              `yaml:"pageInfo"`
 	Nodes      []PullRequestNodeItem `yaml:"nodes"`
 }
 
+// PullRequestReviewComments holds the number of inline comments of a review
+type PullRequestReviewComments struct {
+	TotalCount int `yaml:"totalCount"`
+}
+
 type PullRequestReviewItem struct {
-	State string `yaml:"state"`
-	URL   string `yaml:"URL"`
+	State       string                    `yaml:"state"`
+	URL         string                    `yaml:"URL"`
+	SubmittedAt time.Time                 `yaml:"submittedAt"`
+	Comments    PullRequestReviewComments `yaml:"comments"`
 }
 
 type PullRequestReviews struct {
 	TotalCount int                     `yaml:"totalCount"`
+	PageInfo   PageInfo                `yaml:"pageInfo"`
 	Nodes      []PullRequestReviewItem `yaml:"nodes"`
 }
 
 type PullRequestReviewFragment struct {
+	ID        string             `yaml:"id"`
 	Title     string             `yaml:"title"`
 	Number    int                `yaml:"number"`
 	URL       string             `yaml:"URL"`
 	CreatedAt time.Time          `yaml:"createdAt"`
-	Reviews   PullRequestReviews `graphql:"reviews(first:5, author: $username)" yaml:"reviews"`
+	UpdatedAt time.Time          `yaml:"updatedAt"`
+	Merged    bool               `yaml:"merged"`
+	Reviews   PullRequestReviews `graphql:"reviews(first: 100, author: $username)" yaml:"reviews"`
 }
 
 type PullRequestReviewNodeItem struct {
@@ -259,6 +542,7 @@ type PullRequestReviewNodeItem struct {
 
 type PullRequestsReviewed struct {
 	IssueCount int                         `yaml:"issueCount"`
+	PageInfo   PageInfo                    `yaml:"pageInfo"`
 	Nodes      []PullRequestReviewNodeItem `yaml:"nodes"`
 }
 
@@ -270,9 +554,11 @@ type PullRequestComment struct {
 	Author    PullRequestCommentAuthor `yaml:"author"`
 	CreatedAt time.Time                `yaml:"createdAt"`
 	URL       string                   `yaml:"URL"`
+	Body      string                   `yaml:"body"`
 }
 type PullRequestCommentsItem struct {
-	Nodes []PullRequestComment `yaml:"nodes"`
+	PageInfo PageInfo             `yaml:"pageInfo"`
+	Nodes    []PullRequestComment `yaml:"nodes"`
 }
 
 type PullRequestCommentedRepository struct {
@@ -284,12 +570,14 @@ type PullRequestCommentedAuthor struct {
 }
 
 type PullRequestCommentedFragment struct {
+	ID         string                         `yaml:"id"`
 	Number     int                            `yaml:"number"`
 	Title      string                         `yaml:"title"`
 	URL        string                         `yaml:"URL"`
+	UpdatedAt  time.Time                      `yaml:"updatedAt"`
 	Repository PullRequestCommentedRepository `yaml:"repository"`
 	Author     PullRequestCommentedAuthor     `yaml:"author"`
-	Comments   PullRequestCommentsItem        `graphql:"comments(first:100, orderBy: {field: UPDATED_AT, direction: ASC} )" yaml:"comments"`
+	Comments   PullRequestCommentsItem        `graphql:"comments(first: 100, orderBy: {field: UPDATED_AT, direction: ASC})" yaml:"comments"`
 }
 
 type PullRequestCommentedItem struct {
@@ -298,9 +586,62 @@ type PullRequestCommentedItem struct {
 
 type PullRequestsCommented struct {
 	IssueCount int                        `yaml:"issueCount"`
+	PageInfo   PageInfo                   `yaml:"pageInfo"`
 	Nodes      []PullRequestCommentedItem `yaml:"nodes"`
 }
 
+// DiscussionAnswer is the comment that has been marked as the answer of a
+// discussion.
+type DiscussionAnswer struct {
+	Author    Author    `yaml:"author"`
+	CreatedAt time.Time `yaml:"createdAt"`
+}
+
+type DiscussionFragment struct {
+	Number     int               `yaml:"number"`
+	Title      string            `yaml:"title"`
+	URL        string            `yaml:"URL"`
+	CreatedAt  time.Time         `yaml:"createdAt"`
+	UpdatedAt  time.Time         `yaml:"updatedAt"`
+	Repository Repository        `yaml:"repository"`
+	Author     Author            `yaml:"author"`
+	Answer     *DiscussionAnswer `yaml:"answer,omitempty"`
+}
+
+type DiscussionNodeItem struct {
+	Discussion DiscussionFragment `graphql:"... on Discussion" yaml:"discussion"`
+}
+
+type DiscussionsCreated struct {
+	IssueCount int                  `yaml:"issueCount"`
+	PageInfo   PageInfo             `yaml:"pageInfo"`
+	Nodes      []DiscussionNodeItem `yaml:"nodes"`
+}
+
+type DiscussionsCommented struct {
+	IssueCount int                  `yaml:"issueCount"`
+	PageInfo   PageInfo             `yaml:"pageInfo"`
+	Nodes      []DiscussionNodeItem `yaml:"nodes"`
+}
+
+type ReleaseNode struct {
+	Name        string     `yaml:"name"`
+	TagName     string     `yaml:"tagName"`
+	URL         string     `yaml:"URL"`
+	CreatedAt   time.Time  `yaml:"createdAt"`
+	PublishedAt time.Time  `yaml:"publishedAt"`
+	IsDraft     bool       `yaml:"isDraft"`
+	Author      Author     `yaml:"author"`
+	Repository  Repository `yaml:"repository"`
+}
+
+// ReleasesCreated holds the published releases that the user authored
+// within the window.
+type ReleasesCreated struct {
+	TotalCount int           `yaml:"totalCount"`
+	Nodes      []ReleaseNode `yaml:"nodes"`
+}
+
 type AssociatedPullRequest struct {
 	Number int    `yaml:"number"`
 	Title  string `yaml:"title"`
@@ -312,17 +653,19 @@ type AssociatedPullRequests struct {
 }
 
 type CommitsByUserTargetHistoryNode struct {
-	CommitUrl              string `yaml:"commitUrl"`
+	CommitUrl              string    `yaml:"commitUrl"`
+	CommittedDate          time.Time `yaml:"committedDate"`
 	AssociatedPullRequests `graphql:"associatedPullRequests(first: 5)" yaml:"associatedPullRequests"`
 }
 
 type CommitsByUserTargetHistory struct {
 	TotalCount int                              `yaml:"totalCount"`
+	PageInfo   PageInfo                         `yaml:"pageInfo"`
 	Nodes      []CommitsByUserTargetHistoryNode `yaml:"nodes"`
 }
 
 type CommitsByUserTargetFragment struct {
-	History CommitsByUserTargetHistory `graphql:"history(author: {id: $userID}, since: $startFrom)" yaml:"history"`
+	History CommitsByUserTargetHistory `graphql:"history(first: 100, after: $cursor, author: $author, since: $since, until: $until)" yaml:"history"`
 }
 
 type CommitsByUserTargetItem struct {
@@ -353,12 +696,13 @@ type RepositoryNodeRefTargetHistoryNode struct {
 
 type RepositoryNodeRefTargetHistory struct {
 	TotalCount int                                  `yaml:"totalCount"`
+	PageInfo   PageInfo                             `yaml:"pageInfo"`
 	Nodes      []RepositoryNodeRefTargetHistoryNode `yaml:"nodes"`
 }
 
 type RepositoryNodeRefTargetFragment struct {
 	CommitURL string                         `yaml:"commitURL"`
-	History   RepositoryNodeRefTargetHistory `graphql:"history(first: 3, author: {id: $userID}, since: $startFrom)" yaml:"history"`
+	History   RepositoryNodeRefTargetHistory `graphql:"history(first: 100, author: $author, since: $since, until: $until)" yaml:"history"`
 }
 
 type RepositoryNodeRefTargetItem struct {
@@ -375,9 +719,12 @@ type RepositoryNode struct {
 }
 
 type Repositories struct {
-	Nodes []RepositoryNode `yaml:"nodes"`
+	PageInfo PageInfo         `yaml:"pageInfo"`
+	Nodes    []RepositoryNode `yaml:"nodes"`
 }
 
+// CommitsByUserInOrg holds the commit history of a user in those public
+// repositories of an organization that the user has committed to.
 type CommitsByUserInOrg struct {
-	Repositories Repositories `graphql:"repositories(first: 25, isArchived: false, visibility: PUBLIC)" yaml:"repositories"`
+	Repositories Repositories `graphql:"repositories(first: 25, after: $cursor, isArchived: $isArchived, visibility: PUBLIC, orderBy: {field: NAME, direction: ASC})" yaml:"repositories"`
 }
@@ -21,19 +21,19 @@ package contributions
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/avast/retry-go"
 	"github.com/shurcooL/githubv4"
 	log "github.com/sirupsen/logrus"
-	"golang.org/x/oauth2"
-	"gopkg.in/yaml.v3"
 	"os"
-	"strings"
+	"path/filepath"
 	"time"
 )
 
 type ContributionReportGenerator struct {
-	client        *githubv4.Client
+	client        Querier
+	cache         *reportCache
 	opts          ContributionReportGeneratorOptions
 	ReportingMode interface{}
 }
@@ -43,37 +43,254 @@ func NewContributionReportGenerator(opts ContributionReportGeneratorOptions) (*C
 	if err != nil {
 		return nil, fmt.Errorf("validation failed: %v", err)
 	}
-	token, err := os.ReadFile(opts.GithubTokenPath)
+	var cache *reportCache
+	if opts.CacheDir != "" {
+		cache = &reportCache{dir: opts.CacheDir}
+	}
+	client := opts.Querier
+	if client == nil && !opts.FromCache {
+		client, err = NewGithubQuerier(opts.GithubTokenPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	client = newRateLimitingQuerier(classifyingQuerier{querier: client}, opts.RateLimitThreshold)
+	return &ContributionReportGenerator{client: client, cache: cache, opts: opts}, nil
+}
+
+// GenerateReport generates the contribution report for the user. If the
+// login of the user doesn't resolve to an active user account, the error
+// wraps ErrUserNotFound, ErrUserDeleted or ErrBotAccount, or is a
+// *UserRenamedError.
+func (g ContributionReportGenerator) GenerateReport(ctx context.Context, userName string) (ContributionReport, error) {
+	user := UserIdentity{Login: userName}
+	if !g.opts.FromCache {
+		var err error
+		user, err = g.lookupUser(ctx, userName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	commitEmails := g.opts.Identities.emails(userName)
+	window := g.opts.Window()
+	scopes := g.opts.scopes()
+	if len(scopes) == 1 {
+		contributionReport, err := g.generateScopeReport(ctx, user, commitEmails, scopes[0], window)
+		if err != nil {
+			return nil, err
+		}
+		err = g.addExternalContributions(ctx, contributionReport, userName, window)
+		if err != nil {
+			return nil, err
+		}
+		return contributionReport, nil
+	}
+	aggregatedReport := &UserContributionReportForScopes{
+		Name:     g.opts.ScopesName,
+		UserName: userName,
+		Window:   window,
+	}
+	for _, scope := range scopes {
+		contributionReport, err := g.generateScopeReport(ctx, user, commitEmails, scope, window)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate report for %s: %w", scope, err)
+		}
+		aggregatedReport.add(scope, contributionReport)
+	}
+	err := g.addExternalContributions(ctx, aggregatedReport, userName, window)
 	if err != nil {
-		return nil, fmt.Errorf("failed to use github token path %s: %v", opts.GithubTokenPath, err)
+		return nil, err
+	}
+	return aggregatedReport, nil
+}
+
+// addExternalContributions adds the contributions of the user outside of
+// GitHub to the report. They are never cached, since they are read from
+// local files. If the user has other handles, the activity under those is
+// added as well.
+func (g ContributionReportGenerator) addExternalContributions(ctx context.Context, report ContributionReport, userName string, window ReportWindow) error {
+	r, ok := report.(interface {
+		externalContributions() *ExternalContributions
+	})
+	if !ok {
+		return nil
+	}
+	externalContributions := r.externalContributions()
+	logins := g.opts.Identities.logins(userName)
+	if g.opts.MailingList != nil {
+		externalContributions.MailingList = g.opts.MailingList.activity(logins, g.opts.EmailMapping, window)
+	}
+	if g.opts.Slack != nil {
+		externalContributions.Slack = g.opts.Slack.activity(logins, g.opts.SlackMapping, window)
+	}
+	for _, login := range logins[1:] {
+		activityCounts, err := g.otherLoginActivity(ctx, login)
+		if err != nil {
+			return fmt.Errorf("failed to generate report for other login %s: %w", login, err)
+		}
+		if activityCounts != nil {
+			externalContributions.OtherLogins = append(externalContributions.OtherLogins, LoginActivity{Login: login, ActivityCounts: activityCounts})
+		}
+	}
+	return nil
+}
+
+// otherLoginActivity returns the activity counts under another handle of
+// the user, or nil if the handle doesn't resolve to an active user account.
+// The contributions outside of GitHub are left out, since they are already
+// attributed to the user.
+func (g ContributionReportGenerator) otherLoginActivity(ctx context.Context, login string) (ActivityCounts, error) {
+	g.opts.Identities = nil
+	g.opts.MailingList = nil
+	g.opts.Slack = nil
+	contributionReport, err := g.GenerateReport(ctx, login)
+	var renamedError *UserRenamedError
+	if errors.Is(err, ErrUserNotFound) || errors.Is(err, ErrUserDeleted) || errors.Is(err, ErrBotAccount) || errors.As(err, &renamedError) {
+		log.WithError(err).Warnf("skipping other login %s", login)
+		return nil, nil
 	}
-	src := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: strings.TrimSpace(string(token))},
+	if err != nil {
+		return nil, err
+	}
+	return contributionReport.ActivityCounts(), nil
+}
+
+// GenerateReportInWindow generates the contribution report for the user
+// over the given window instead of the window of the options.
+func (g ContributionReportGenerator) GenerateReportInWindow(ctx context.Context, userName string, window ReportWindow) (ContributionReport, error) {
+	g.opts.Since = window.Since
+	g.opts.Until = window.Until
+	return g.GenerateReport(ctx, userName)
+}
+
+// lookupUser resolves the identity of the user, retrying transient
+// failures.
+func (g ContributionReportGenerator) lookupUser(ctx context.Context, userName string) (UserIdentity, error) {
+	var user UserIdentity
+	err := retry.Do(
+		func() error {
+			var err error
+			user, err = lookupUser(ctx, g.client, userName, g.opts.KnownUsers)
+			if err != nil && IsTransient(err) {
+				log.Errorf("user lookup failed (will retry): %v", err)
+			}
+			return err
+		},
+		g.opts.retryOptions(ctx)...,
 	)
-	httpClient := oauth2.NewClient(context.Background(), src)
-	client := githubv4.NewClient(httpClient)
-	return &ContributionReportGenerator{client: client, opts: opts}, nil
+	return user, err
+}
+
+// generateScopeReport generates the report for the user in the scope,
+// including the commits authored with any of the commit emails.
+func (g ContributionReportGenerator) generateScopeReport(ctx context.Context, user UserIdentity, commitEmails []string, scope Scope, window ReportWindow) (ContributionReport, error) {
+	if g.cache != nil {
+		return g.generateCachedReport(ctx, user, commitEmails, scope, window)
+	}
+	return g.queryReport(ctx, user, commitEmails, scope, window, time.Time{})
+}
+
+// generateCachedReport returns the cached report for the user trimmed to
+// the window, after updating it with the activity that happened since it
+// was last fetched. If the cached report doesn't reach back to the start
+// of the window, the whole window is fetched again. If the options are set
+// to use the cache only, the cached report is returned without updating it.
+func (g ContributionReportGenerator) generateCachedReport(ctx context.Context, user UserIdentity, commitEmails []string, scope Scope, window ReportWindow) (ContributionReport, error) {
+	userName := user.Login
+	key := cacheKey{
+		org:             scope.Org,
+		repo:            scope.Repo,
+		userName:        userName,
+		includeArchived: scope.Repo == "" && g.opts.IncludeArchived,
+		commitEmails:    commitEmails,
+	}
+	entry, err := g.cache.load(key)
+	if err != nil {
+		return nil, err
+	}
+	if g.opts.FromCache {
+		if entry == nil {
+			return nil, fmt.Errorf("no cached report for user %s", userName)
+		}
+		if entry.Window.Since.After(window.Since) {
+			log.Warnf("cached report for user %s only contains the activity since %s", userName, entry.Window.Since.Format(time.DateOnly))
+		}
+		return entry.trimmed(window).report(), nil
+	}
+	if entry != nil && (entry.Window.Since.After(window.Since) || entry.Window.Until.Before(window.Since)) {
+		log.Debugf("cached report for user %s doesn't cover the start of the window", userName)
+		entry = nil
+	}
+	if entry != nil {
+		entry = entry.trimmed(ReportWindow{Since: window.Since, Until: entry.Window.Until})
+		if !entry.Window.Until.Before(window.Until) {
+			log.Debugf("cached report for user %s is complete", userName)
+			return entry.trimmed(window).report(), nil
+		}
+	}
+
+	fetchedAt := time.Now()
+	queryWindow := window
+	var commentedIssuesSince time.Time
+	if entry != nil {
+		queryWindow.Since = entry.Window.Until
+		// the user might since have commented on issues that were created
+		// within the window before the cached report was fetched
+		commentedIssuesSince = window.Since
+		log.Debugf("updating cached report for user %s with activity since %s", userName, queryWindow.Since.Format(time.DateTime))
+	}
+	contributionReport, err := g.queryReport(ctx, user, commitEmails, scope, queryWindow, commentedIssuesSince)
+	if err != nil {
+		return nil, err
+	}
+	switch r := contributionReport.(type) {
+	case *UserContributionReportForRepository:
+		if entry == nil {
+			entry = &cacheEntry{RepositoryReport: r}
+		} else {
+			entry.RepositoryReport.merge(r)
+		}
+	case *UserContributionReportForOrganization:
+		if entry == nil {
+			entry = &cacheEntry{OrganizationReport: r}
+		} else {
+			entry.OrganizationReport.merge(r)
+		}
+	}
+	// the activity has been fetched up to the end of the window, unless
+	// the window ends in the future
+	entry.Window = ReportWindow{Since: window.Since, Until: window.Until}
+	if fetchedAt.Before(window.Until) {
+		entry.Window.Until = fetchedAt
+	}
+	err = g.cache.store(key, entry)
+	if err != nil {
+		log.WithError(err).Warnf("failed to cache report for user %s", userName)
+	}
+	return entry.trimmed(window).report(), nil
 }
 
-func (g ContributionReportGenerator) GenerateReport(userName string) (ContributionReport, error) {
+// queryReport fetches the report of the user within the window, see
+// queryUserContributions for commentedIssuesSince.
+func (g ContributionReportGenerator) queryReport(ctx context.Context, user UserIdentity, commitEmails []string, scope Scope, window ReportWindow, commentedIssuesSince time.Time) (ContributionReport, error) {
 	var contributionReport ContributionReport
 	err := retry.Do(
 		func() error {
 			var err error
-			if g.opts.Repo != "" {
-				contributionReport, err = generateUserActivityReportForRepository(g.client, g.opts.Org, g.opts.Repo, userName, g.opts.startFrom())
+			if scope.Repo != "" {
+				contributionReport, err = generateUserActivityReportForRepository(ctx, g.client, scope.Org, scope.Repo, user, commitEmails, window, commentedIssuesSince, g.opts.MaxPages)
 			} else {
-				contributionReport, err = generateUserContributionReportForOrganization(g.client, g.opts.Org, userName, g.opts.startFrom())
+				contributionReport, err = generateUserContributionReportForOrganization(ctx, g.client, scope.Org, user, commitEmails, window, commentedIssuesSince, g.opts.IncludeArchived, g.opts.MaxPages)
 			}
-			if err != nil {
+			if err != nil && IsTransient(err) {
 				log.Errorf("query failed (will retry): %v", err)
 			}
 			return err
 		},
-		retry.LastErrorOnly(true),
+		g.opts.retryOptions(ctx)...,
 	)
-	if contributionReport == nil && err != nil {
-		return nil, fmt.Errorf("query failed (aborting): %v", err)
+	if err != nil {
+		return nil, fmt.Errorf("query failed (aborting): %w", err)
 	}
 	return contributionReport, nil
 }
@@ -83,202 +300,648 @@ type ContributionReportGeneratorOptions struct {
 	Repo            string
 	GithubTokenPath string
 	Months          int
+
+	// Scopes are the repositories or organizations to aggregate the reports
+	// over, where ScopesName is the name of the set of scopes (i.e. the
+	// name of a SIG). If set, Org and Repo are ignored.
+	Scopes     []Scope
+	ScopesName string
+
+	// IncludeArchived determines whether archived repositories are included
+	// when collecting the commits of a user in an organization.
+	IncludeArchived bool
+
+	// Since and Until define the window of the report. If Since is not set,
+	// the window starts Months before Until. If Until is not set, the window
+	// ends now.
+	Since time.Time
+	Until time.Time
+
+	// MaxPages limits the number of pages fetched per category, where zero
+	// means that all pages are fetched.
+	MaxPages int
+
+	// Querier is used to run the queries against GitHub. If nil, a client
+	// for the GitHub API is created using the token from GithubTokenPath.
+	Querier Querier
+
+	// RetryAttempts is the number of attempts to generate a report, where
+	// zero means the default number of attempts.
+	RetryAttempts uint
+
+	// RetryDelay is the initial delay between attempts, where zero means
+	// the default delay.
+	RetryDelay time.Duration
+
+	// RateLimitThreshold is the number of remaining rate limit points below
+	// which queries are held back until the rate limit has been reset.
+	RateLimitThreshold int
+
+	// CacheDir is the directory where query results are cached. If empty,
+	// no cache is used.
+	CacheDir string
+
+	// FromCache determines whether reports are generated from the cache
+	// only, without querying GitHub.
+	FromCache bool
+
+	// KnownUsers are the identities of users from previous reports, keyed
+	// by lower case login. They are used to tell renamed from deleted
+	// accounts when a login doesn't resolve to a user anymore.
+	KnownUsers map[string]UserIdentity
+
+	// MailingList is the archive of the mailing list whose mails are
+	// counted as contributions, where the senders are resolved to users
+	// through EmailMapping. If nil, no mails are counted.
+	MailingList  *MailingListArchive
+	EmailMapping EmailMapping
+
+	// Slack is the export of the Slack workspace whose messages are counted
+	// as contributions, where the Slack users are resolved to users through
+	// SlackMapping. If nil, no messages are counted.
+	Slack        *SlackExport
+	SlackMapping SlackMapping
+
+	// Identities map people to all their GitHub handles and commit emails.
+	// The commits authored with the emails are added to the report of the
+	// user, and the activity under the other handles is added to the
+	// report. If nil, reports only cover the handle they are generated for.
+	Identities *Identities
 }
 
 func (o ContributionReportGeneratorOptions) validate() error {
-	if o.GithubTokenPath == "" {
+	if o.GithubTokenPath == "" && o.Querier == nil && !o.FromCache {
 		return fmt.Errorf("github token path is required")
 	}
+	if !o.Since.IsZero() && !o.Until.IsZero() && !o.Since.Before(o.Until) {
+		return fmt.Errorf("since must be before until")
+	}
+	if o.FromCache && o.CacheDir == "" {
+		return fmt.Errorf("cache dir is required to generate reports from cache")
+	}
+	err := validateScopes(o.Scopes)
+	if err != nil {
+		return err
+	}
+	if o.MaxPages < 0 {
+		return fmt.Errorf("max pages must not be negative")
+	}
+	if o.RateLimitThreshold < 0 {
+		return fmt.Errorf("rate limit threshold must not be negative")
+	}
+	if o.MailingList != nil && o.EmailMapping == nil {
+		return fmt.Errorf("email mapping is required to count mailing list contributions")
+	}
+	if o.Slack != nil && o.SlackMapping == nil {
+		return fmt.Errorf("slack mapping is required to count slack contributions")
+	}
 	return nil
 }
 
-func (o ContributionReportGeneratorOptions) startFrom() time.Time {
-	return time.Now().AddDate(0, -1*o.Months, 0)
-}
-
-func generateUserActivityReportForRepository(client *githubv4.Client, org, repo, username string, startFrom time.Time) (*UserContributionReportForRepository, error) {
-	userid, err := getUserId(client, username)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query: %v", err)
-	}
-
-	var query struct {
-		IssuesCreated         IssuesCreated         `graphql:"issuesCreated: search(first: 5, type: ISSUE, query: $authorSearchQuery)"`
-		IssuesCommented       IssuesCommented       `graphql:"issuesCommented: search(first: 5, type: ISSUE, query: $commenterSearchQuery)"`
-		PullRequestsCreated   PullRequestsCreated   `graphql:"prsCreated: search(type: ISSUE, first: 5, query: $pullRequestsCreatedQuery)"`
-		PullRequestsReviewed  PullRequestsReviewed  `graphql:"prsReviewed: search(type: ISSUE, first: 5, query: $pullRequestsReviewedQuery)"`
-		PullRequestsCommented PullRequestsCommented `graphql:"prsCommented: search(last: 100, type: ISSUE, query: $pullRequestsCommentedQuery)"`
-		CommitsByUser         CommitsByUser         `graphql:"commitsByUser: repository(owner: $org, name: $repo)"`
-	}
-
-	fromDate := startFrom.Format("2006-01-02")
-
-	variables := map[string]interface{}{
-		"org":       githubv4.String(org),
-		"repo":      githubv4.String(repo),
-		"username":  githubv4.String(username),
-		"userID":    githubv4.ID(userid),
-		"startFrom": githubv4.GitTimestamp{Time: startFrom},
-		"authorSearchQuery": githubv4.String(fmt.Sprintf(
-			"repo:%s/%s author:%s is:issue created:>=%s",
-			org,
-			repo,
-			username,
-			fromDate,
-		)),
-		"commenterSearchQuery": githubv4.String(fmt.Sprintf(
-			"repo:%s/%s commenter:%s is:issue created:>=%s",
-			org,
-			repo,
-			username,
-			fromDate,
-		)),
-		"pullRequestsCreatedQuery": githubv4.String(fmt.Sprintf(
-			"repo:%s/%s author:%s is:pr created:>=%s",
-			org,
-			repo,
-			username,
-			fromDate,
-		)),
-		"pullRequestsReviewedQuery": githubv4.String(fmt.Sprintf(
-			"repo:%s/%s reviewed-by:%s is:pr updated:>=%s",
-			org,
-			repo,
-			username,
-			fromDate,
-		)),
-		"pullRequestsCommentedQuery": githubv4.String(fmt.Sprintf(
-			"repo:%s/%s commenter:%s is:pr updated:>=%s",
-			org,
-			repo,
-			username,
-			fromDate,
-		)),
-	}
-
-	err = client.Query(context.Background(), &query, variables)
-	if err != nil {
-		return nil, fmt.Errorf("failed to use github query %+v with variables %v: %w", query, variables, err)
+func (o ContributionReportGeneratorOptions) retryOptions(ctx context.Context) []retry.Option {
+	retryOptions := []retry.Option{
+		retry.Context(ctx),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(IsTransient),
+		retry.DelayType(retryAfterDelay),
+	}
+	if o.RetryAttempts > 0 {
+		retryOptions = append(retryOptions, retry.Attempts(o.RetryAttempts))
 	}
+	if o.RetryDelay > 0 {
+		retryOptions = append(retryOptions, retry.Delay(o.RetryDelay))
+	}
+	return retryOptions
+}
+
+// retryAfterDelay waits as long as the server asked for, or backs off
+// exponentially otherwise.
+func retryAfterDelay(n uint, err error, config *retry.Config) time.Duration {
+	if delay := retryAfter(err); delay > 0 {
+		return delay
+	}
+	return retry.BackOffDelay(n, err, config)
+}
+
+func (o ContributionReportGeneratorOptions) scopes() []Scope {
+	if len(o.Scopes) > 0 {
+		return o.Scopes
+	}
+	return []Scope{{Org: o.Org, Repo: o.Repo}}
+}
+
+// Window returns the window that the reports cover.
+func (o ContributionReportGeneratorOptions) Window() ReportWindow {
+	until := o.Until
+	if until.IsZero() {
+		until = time.Now()
+	}
+	since := o.Since
+	if since.IsZero() {
+		since = until.AddDate(0, -1*o.Months, 0)
+	}
+	return ReportWindow{Since: since, Until: until}
+}
+
+func generateUserActivityReportForRepository(ctx context.Context, client Querier, org, repo string, user UserIdentity, commitEmails []string, window ReportWindow, commentedIssuesSince time.Time, maxPages int) (*UserContributionReportForRepository, error) {
+	userContributions, err := queryUserContributions(ctx, client, Scope{Org: org, Repo: repo}.searchQualifier(), user.Login, window, commentedIssuesSince, maxPages)
+	if err != nil {
+		return nil, err
+	}
+
+	commitsByUser, err := queryCommitsOfUser(ctx, client, org, repo, commitAuthors(user, commitEmails), window, maxPages)
+	if err != nil {
+		return nil, err
+	}
+
+	userContributions.ReleasesCreated, err = queryReleasesByUser(ctx, client, org, repo, user.Login, window, maxPages)
+	if err != nil {
+		return nil, err
+	}
+
 	return &UserContributionReportForRepository{
-		IssuesCreated:         query.IssuesCreated,
-		IssuesCommented:       query.IssuesCommented,
-		PullRequestsCreated:   query.PullRequestsCreated,
-		PullRequestsReviewed:  query.PullRequestsReviewed,
-		PullRequestsCommented: query.PullRequestsCommented,
-		CommitsByUser:         query.CommitsByUser,
-		Org:                   org,
-		Repo:                  repo,
-		UserName:              username,
-		UserID:                userid,
-		StartFrom:             startFrom,
+		UserContributions: *userContributions,
+		CommitsByUser:     *commitsByUser,
+		Org:               org,
+		Repo:              repo,
+		UserName:          user.Login,
+		UserID:            user.ID,
+		UserDatabaseID:    user.DatabaseID,
+		Window:            window,
 	}, nil
 }
 
-func generateUserContributionReportForOrganization(client *githubv4.Client, org, username string, startFrom time.Time) (*UserContributionReportForOrganization, error) {
-	userid, err := getUserId(client, username)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query: %v", err)
-	}
-
-	var query struct {
-		IssuesCreated         IssuesCreated         `graphql:"issuesCreated: search(first: 5, type: ISSUE, query: $authorSearchQuery)"`
-		IssuesCommented       IssuesCommented       `graphql:"issuesCommented: search(first: 5, type: ISSUE, query: $commenterSearchQuery)"`
-		PullRequestsCreated   PullRequestsCreated   `graphql:"prsCreated: search(type: ISSUE, first: 5, query: $pullRequestsCreatedQuery)"`
-		PullRequestsReviewed  PullRequestsReviewed  `graphql:"prsReviewed: search(type: ISSUE, first: 5, query: $pullRequestsReviewedQuery)"`
-		PullRequestsCommented PullRequestsCommented `graphql:"prsCommented: search(last: 100, type: ISSUE, query: $pullRequestsCommentedQuery)"`
-		CommitsByUserInOrg    CommitsByUserInOrg    `graphql:"commitsByUserInOrg: organization(login: $org)"`
-	}
-
-	fromDate := startFrom.Format("2006-01-02")
-
-	variables := map[string]interface{}{
-		"org":       githubv4.String(org),
-		"username":  githubv4.String(username),
-		"userID":    githubv4.ID(userid),
-		"startFrom": githubv4.GitTimestamp{Time: startFrom},
-		"authorSearchQuery": githubv4.String(fmt.Sprintf(
-			"org:%s author:%s is:issue created:>=%s",
-			org,
-			username,
-			fromDate,
-		)),
-		"commenterSearchQuery": githubv4.String(fmt.Sprintf(
-			"org:%s commenter:%s is:issue created:>=%s",
-			org,
-			username,
-			fromDate,
-		)),
-		"pullRequestsCreatedQuery": githubv4.String(fmt.Sprintf(
-			"org:%s author:%s is:pr created:>=%s",
-			org,
-			username,
-			fromDate,
-		)),
-		"pullRequestsReviewedQuery": githubv4.String(fmt.Sprintf(
-			"org:%s reviewed-by:%s is:pr updated:>=%s",
-			org,
-			username,
-			fromDate,
-		)),
-		"pullRequestsCommentedQuery": githubv4.String(fmt.Sprintf(
-			"org:%s commenter:%s is:pr updated:>=%s",
-			org,
-			username,
-			fromDate,
-		)),
-	}
-
-	err = client.Query(context.Background(), &query, variables)
-	if err != nil {
-		return nil, fmt.Errorf("failed to use github query %+v with variables %v: %w", query, variables, err)
+func generateUserContributionReportForOrganization(ctx context.Context, client Querier, org string, user UserIdentity, commitEmails []string, window ReportWindow, commentedIssuesSince time.Time, includeArchived bool, maxPages int) (*UserContributionReportForOrganization, error) {
+	userContributions, err := queryUserContributions(ctx, client, Scope{Org: org}.searchQualifier(), user.Login, window, commentedIssuesSince, maxPages)
+	if err != nil {
+		return nil, err
 	}
+
+	commitsByUserInOrg, err := queryCommitsOfUserInOrg(ctx, client, org, commitAuthors(user, commitEmails), window, includeArchived, maxPages)
+	if err != nil {
+		return nil, err
+	}
+
+	userContributions.ReleasesCreated, err = queryReleasesByUserInOrg(ctx, client, org, user.Login, window, includeArchived, maxPages)
+	if err != nil {
+		return nil, err
+	}
+
 	return &UserContributionReportForOrganization{
-		IssuesCreated:         query.IssuesCreated,
-		IssuesCommented:       query.IssuesCommented,
-		PullRequestsCreated:   query.PullRequestsCreated,
-		PullRequestsReviewed:  query.PullRequestsReviewed,
-		PullRequestsCommented: query.PullRequestsCommented,
-		CommitsByUserInOrg:    query.CommitsByUserInOrg,
-		Org:                   org,
-		UserName:              username,
-		UserID:                userid,
-		StartFrom:             startFrom,
+		UserContributions:  *userContributions,
+		CommitsByUserInOrg: *commitsByUserInOrg,
+		Org:                org,
+		UserName:           user.Login,
+		UserID:             user.ID,
+		UserDatabaseID:     user.DatabaseID,
+		Window:             window,
+		IncludeArchived:    includeArchived,
 	}, nil
 }
 
-func getUserId(client *githubv4.Client, username string) (string, error) {
-	var query struct {
-		User struct {
-			ID string
-		} `graphql:"user(login: $username)"`
+// queryUserContributions runs all searches for the given search scope
+// (i.e. `org:kubevirt` or `repo:kubevirt/kubevirt`) within the window and
+// fetches all pages of the results, including nested comments and reviews.
+// If commentedIssuesSince is set, the commented issues are those created
+// since then that have been updated within the window instead. This is used
+// to update a cached report, where a comment within the window on an issue
+// that was created before it needs to be found as well.
+func queryUserContributions(ctx context.Context, client Querier, scope, username string, window ReportWindow, commentedIssuesSince time.Time, maxPages int) (*UserContributions, error) {
+	searchQuery := func(format string) string {
+		return fmt.Sprintf(format, scope, username, window.searchRange())
+	}
+	issuesCommentedQuery := searchQuery("%s commenter:%s is:issue created:%s")
+	if !commentedIssuesSince.IsZero() {
+		createdWindow := ReportWindow{Since: commentedIssuesSince, Until: window.Until}
+		issuesCommentedQuery = fmt.Sprintf("%s commenter:%s is:issue created:%s updated:%s", scope, username, createdWindow.searchRange(), window.searchRange())
 	}
-	variables := map[string]interface{}{
+
+	issuesCreated, err := searchAll[IssuesCreatedNodeItem](ctx, client, githubv4.SearchTypeIssue,
+		searchQuery("%s author:%s is:issue created:%s"), nil, maxPages)
+	if err != nil {
+		return nil, err
+	}
+	issuesCommented, err := searchAll[IssuesCommentedNodeItem](ctx, client, githubv4.SearchTypeIssue,
+		issuesCommentedQuery, nil, maxPages)
+	if err != nil {
+		return nil, err
+	}
+	for i := range issuesCommented.Nodes {
+		issue := &issuesCommented.Nodes[i].Issue
+		err = fetchRemainingIssueComments(ctx, client, issue.ID, &issue.Comments, maxPages)
+		if err != nil {
+			return nil, err
+		}
+	}
+	pullRequestsCreated, err := searchAll[PullRequestNodeItem](ctx, client, githubv4.SearchTypeIssue,
+		searchQuery("%s author:%s is:pr created:%s"), nil, maxPages)
+	if err != nil {
+		return nil, err
+	}
+	usernameVariable := map[string]interface{}{
 		"username": githubv4.String(username),
 	}
-	err := client.Query(context.Background(), &query, variables)
+	pullRequestsReviewed, err := searchAll[PullRequestReviewNodeItem](ctx, client, githubv4.SearchTypeIssue,
+		searchQuery("%s reviewed-by:%s is:pr updated:%s"), usernameVariable, maxPages)
 	if err != nil {
-		return "", fmt.Errorf("failed to use github query %+v with variables %v: %w", query, variables, err)
+		return nil, err
+	}
+	for i := range pullRequestsReviewed.Nodes {
+		pullRequest := &pullRequestsReviewed.Nodes[i].PullRequestReview
+		err = fetchRemainingPullRequestReviews(ctx, client, pullRequest.ID, username, &pullRequest.Reviews, maxPages)
+		if err != nil {
+			return nil, err
+		}
+	}
+	pullRequestsCommented, err := searchAll[PullRequestCommentedItem](ctx, client, githubv4.SearchTypeIssue,
+		searchQuery("%s commenter:%s is:pr updated:%s"), nil, maxPages)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pullRequestsCommented.Nodes {
+		pullRequest := &pullRequestsCommented.Nodes[i].PullRequest
+		err = fetchRemainingPullRequestComments(ctx, client, pullRequest.ID, &pullRequest.Comments, maxPages)
+		if err != nil {
+			return nil, err
+		}
 	}
-	return query.User.ID, nil
-}
 
-func writeActivityToFile(yamlObject interface{}, dir, fileName string) error {
-	tempFile, err := os.CreateTemp(dir, fileName)
+	discussionsCreated, err := searchAll[DiscussionNodeItem](ctx, client, githubv4.SearchTypeDiscussion,
+		searchQuery("%s author:%s created:%s"), nil, maxPages)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	discussionsCommented, err := searchAll[DiscussionNodeItem](ctx, client, githubv4.SearchTypeDiscussion,
+		searchQuery("%s commenter:%s updated:%s"), nil, maxPages)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserContributions{
+		IssuesCreated:         IssuesCreated(issuesCreated),
+		IssuesCommented:       IssuesCommented(issuesCommented),
+		PullRequestsCreated:   PullRequestsCreated(pullRequestsCreated),
+		PullRequestsReviewed:  PullRequestsReviewed(pullRequestsReviewed),
+		PullRequestsCommented: PullRequestsCommented(pullRequestsCommented),
+		DiscussionsCreated:    DiscussionsCreated(discussionsCreated),
+		DiscussionsCommented:  DiscussionsCommented(discussionsCommented),
+	}, nil
+}
+
+// searchConnection is the shape of a search result, it is convertible into
+// each of the search result types i.e. IssuesCreated. IssueCount is the
+// number of results, regardless of the type of the search.
+type searchConnection[T any] struct {
+	IssueCount int
+	PageInfo   PageInfo
+	Nodes      []T
+}
+
+// searchResult is the shape of a search query, where only the count of
+// the searched type is set.
+type searchResult[T any] struct {
+	IssueCount      int
+	DiscussionCount int
+	PageInfo        PageInfo
+	Nodes           []T
+}
+
+// searchAll fetches the pages of results of the searchType for the
+// searchQuery, where the nodes are of type T. The variables are added to
+// the query variables, they need to contain all variables that are
+// referenced from T.
+func searchAll[T any](ctx context.Context, client Querier, searchType githubv4.SearchType, searchQuery string, variables map[string]interface{}, maxPages int) (searchConnection[T], error) {
+	var result searchConnection[T]
+	queryVariables := map[string]interface{}{
+		"searchQuery": githubv4.String(searchQuery),
+		"searchType":  searchType,
+	}
+	for key, value := range variables {
+		queryVariables[key] = value
 	}
-	defer func() {
-		err := tempFile.Close()
+	fetchPage := func(cursor *githubv4.String) (PageInfo, error) {
+		var query struct {
+			Search searchResult[T] `graphql:"search(first: 50, after: $cursor, type: $searchType, query: $searchQuery)"`
+		}
+		queryVariables["cursor"] = cursor
+		err := client.Query(ctx, &query, queryVariables)
 		if err != nil {
-			log.WithError(err).Errorf("failed to close tempFile")
+			return PageInfo{}, fmt.Errorf("failed to use github query %+v with variables %v: %w", query, queryVariables, err)
 		}
-	}()
-	encoder := yaml.NewEncoder(tempFile)
-	err = encoder.Encode(&yamlObject)
+		result.IssueCount = query.Search.IssueCount + query.Search.DiscussionCount
+		result.PageInfo = query.Search.PageInfo
+		result.Nodes = append(result.Nodes, query.Search.Nodes...)
+		return query.Search.PageInfo, nil
+	}
+	pageInfo, err := fetchPage(nil)
 	if err != nil {
-		return err
+		return result, err
+	}
+	err = walkPages(pageInfo, maxPages, fetchPage)
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// walkPages fetches the remaining pages of a connection where pageInfo
+// describes the first page, until either there are no more pages or
+// maxPages pages (including the first one) have been fetched.
+func walkPages(pageInfo PageInfo, maxPages int, fetchPage func(cursor *githubv4.String) (PageInfo, error)) error {
+	for page := 1; pageInfo.HasNextPage && (maxPages == 0 || page < maxPages); page++ {
+		var err error
+		pageInfo, err = fetchPage(githubv4.NewString(githubv4.String(pageInfo.EndCursor)))
+		if err != nil {
+			return err
+		}
 	}
-	log.Debugf(`user activity log: %q`, tempFile.Name())
 	return nil
 }
+
+func fetchRemainingIssueComments(ctx context.Context, client Querier, issueID string, comments *Comments, maxPages int) error {
+	return walkPages(comments.PageInfo, maxPages, func(cursor *githubv4.String) (PageInfo, error) {
+		var query struct {
+			Node struct {
+				Issue struct {
+					Comments Comments `graphql:"comments(first: 100, after: $cursor, orderBy: {field: UPDATED_AT, direction: ASC})"`
+				} `graphql:"... on Issue"`
+			} `graphql:"node(id: $id)"`
+		}
+		variables := map[string]interface{}{
+			"id":     githubv4.ID(issueID),
+			"cursor": cursor,
+		}
+		err := client.Query(ctx, &query, variables)
+		if err != nil {
+			return PageInfo{}, fmt.Errorf("failed to use github query %+v with variables %v: %w", query, variables, err)
+		}
+		comments.PageInfo = query.Node.Issue.Comments.PageInfo
+		comments.Nodes = append(comments.Nodes, query.Node.Issue.Comments.Nodes...)
+		return comments.PageInfo, nil
+	})
+}
+
+func fetchRemainingPullRequestComments(ctx context.Context, client Querier, pullRequestID string, comments *PullRequestCommentsItem, maxPages int) error {
+	return walkPages(comments.PageInfo, maxPages, func(cursor *githubv4.String) (PageInfo, error) {
+		var query struct {
+			Node struct {
+				PullRequest struct {
+					Comments PullRequestCommentsItem `graphql:"comments(first: 100, after: $cursor, orderBy: {field: UPDATED_AT, direction: ASC})"`
+				} `graphql:"... on PullRequest"`
+			} `graphql:"node(id: $id)"`
+		}
+		variables := map[string]interface{}{
+			"id":     githubv4.ID(pullRequestID),
+			"cursor": cursor,
+		}
+		err := client.Query(ctx, &query, variables)
+		if err != nil {
+			return PageInfo{}, fmt.Errorf("failed to use github query %+v with variables %v: %w", query, variables, err)
+		}
+		comments.PageInfo = query.Node.PullRequest.Comments.PageInfo
+		comments.Nodes = append(comments.Nodes, query.Node.PullRequest.Comments.Nodes...)
+		return comments.PageInfo, nil
+	})
+}
+
+func fetchRemainingPullRequestReviews(ctx context.Context, client Querier, pullRequestID, username string, reviews *PullRequestReviews, maxPages int) error {
+	return walkPages(reviews.PageInfo, maxPages, func(cursor *githubv4.String) (PageInfo, error) {
+		var query struct {
+			Node struct {
+				PullRequest struct {
+					Reviews PullRequestReviews `graphql:"reviews(first: 100, after: $cursor, author: $username)"`
+				} `graphql:"... on PullRequest"`
+			} `graphql:"node(id: $id)"`
+		}
+		variables := map[string]interface{}{
+			"id":       githubv4.ID(pullRequestID),
+			"username": githubv4.String(username),
+			"cursor":   cursor,
+		}
+		err := client.Query(ctx, &query, variables)
+		if err != nil {
+			return PageInfo{}, fmt.Errorf("failed to use github query %+v with variables %v: %w", query, variables, err)
+		}
+		reviews.PageInfo = query.Node.PullRequest.Reviews.PageInfo
+		reviews.Nodes = append(reviews.Nodes, query.Node.PullRequest.Reviews.Nodes...)
+		return reviews.PageInfo, nil
+	})
+}
+
+// commitAuthors returns the authors to query the commits of the user with,
+// which are the account of the user and, if given, the commit emails of the
+// user, since GitHub ignores the emails if the account is given.
+func commitAuthors(user UserIdentity, commitEmails []string) []githubv4.CommitAuthor {
+	id := githubv4.ID(user.ID)
+	authors := []githubv4.CommitAuthor{{ID: &id}}
+	if len(commitEmails) > 0 {
+		var emails []githubv4.String
+		for _, email := range commitEmails {
+			emails = append(emails, githubv4.String(email))
+		}
+		authors = append(authors, githubv4.CommitAuthor{Emails: &emails})
+	}
+	return authors
+}
+
+// queryCommitsOfUser returns the commits in the repository that match any
+// of the authors.
+func queryCommitsOfUser(ctx context.Context, client Querier, org, repo string, authors []githubv4.CommitAuthor, window ReportWindow, maxPages int) (*CommitsByUser, error) {
+	var commitsOfUser *CommitsByUser
+	for _, author := range authors {
+		commitsByUser, err := queryCommitsByUser(ctx, client, org, repo, author, window, maxPages)
+		if err != nil {
+			return nil, err
+		}
+		if commitsOfUser == nil {
+			commitsOfUser = commitsByUser
+			continue
+		}
+		commitsOfUser.merge(*commitsByUser)
+	}
+	return commitsOfUser, nil
+}
+
+// queryCommitsOfUserInOrg returns the commits in the org that match any of
+// the authors.
+func queryCommitsOfUserInOrg(ctx context.Context, client Querier, org string, authors []githubv4.CommitAuthor, window ReportWindow, includeArchived bool, maxPages int) (*CommitsByUserInOrg, error) {
+	var commitsOfUserInOrg *CommitsByUserInOrg
+	for _, author := range authors {
+		commitsByUserInOrg, err := queryCommitsByUserInOrg(ctx, client, org, author, window, includeArchived, maxPages)
+		if err != nil {
+			return nil, err
+		}
+		if commitsOfUserInOrg == nil {
+			commitsOfUserInOrg = commitsByUserInOrg
+			continue
+		}
+		commitsOfUserInOrg.merge(*commitsByUserInOrg)
+	}
+	return commitsOfUserInOrg, nil
+}
+
+func queryCommitsByUser(ctx context.Context, client Querier, org, repo string, author githubv4.CommitAuthor, window ReportWindow, maxPages int) (*CommitsByUser, error) {
+	var commitsByUser CommitsByUser
+	history := &commitsByUser.DefaultBranchRef.Target.Fragment.History
+	fetchPage := func(cursor *githubv4.String) (PageInfo, error) {
+		var query struct {
+			CommitsByUser CommitsByUser `graphql:"commitsByUser: repository(owner: $org, name: $repo)"`
+		}
+		variables := map[string]interface{}{
+			"org":    githubv4.String(org),
+			"repo":   githubv4.String(repo),
+			"author": author,
+			"since":  githubv4.GitTimestamp{Time: window.Since},
+			"until":  githubv4.GitTimestamp{Time: window.Until},
+			"cursor": cursor,
+		}
+		err := client.Query(ctx, &query, variables)
+		if err != nil {
+			return PageInfo{}, fmt.Errorf("failed to use github query %+v with variables %v: %w", query, variables, err)
+		}
+		page := query.CommitsByUser.DefaultBranchRef.Target.Fragment.History
+		history.TotalCount = page.TotalCount
+		history.PageInfo = page.PageInfo
+		history.Nodes = append(history.Nodes, page.Nodes...)
+		return page.PageInfo, nil
+	}
+	pageInfo, err := fetchPage(nil)
+	if err != nil {
+		return nil, err
+	}
+	err = walkPages(pageInfo, maxPages, fetchPage)
+	if err != nil {
+		return nil, err
+	}
+	return &commitsByUser, nil
+}
+
+// queryCommitsByUserInOrg pages through the public repositories of the org
+// (including the archived ones if includeArchived is set) and fetches the
+// commit history of the user within the window for each of them. Only the
+// repositories that the user has committed to are kept.
+func queryCommitsByUserInOrg(ctx context.Context, client Querier, org string, author githubv4.CommitAuthor, window ReportWindow, includeArchived bool, maxPages int) (*CommitsByUserInOrg, error) {
+	var isArchived *githubv4.Boolean
+	if !includeArchived {
+		isArchived = githubv4.NewBoolean(false)
+	}
+	var commitsByUserInOrg CommitsByUserInOrg
+	repositories := &commitsByUserInOrg.Repositories
+	fetchPage := func(cursor *githubv4.String) (PageInfo, error) {
+		var query struct {
+			CommitsByUserInOrg CommitsByUserInOrg `graphql:"commitsByUserInOrg: organization(login: $org)"`
+		}
+		variables := map[string]interface{}{
+			"org":        githubv4.String(org),
+			"author":     author,
+			"since":      githubv4.GitTimestamp{Time: window.Since},
+			"until":      githubv4.GitTimestamp{Time: window.Until},
+			"isArchived": isArchived,
+			"cursor":     cursor,
+		}
+		err := client.Query(ctx, &query, variables)
+		if err != nil {
+			return PageInfo{}, fmt.Errorf("failed to use github query %+v with variables %v: %w", query, variables, err)
+		}
+		page := query.CommitsByUserInOrg.Repositories
+		for _, node := range page.Nodes {
+			if node.DefaultBranchRef.Target.Fragment.History.TotalCount == 0 {
+				continue
+			}
+			err := fetchRemainingRepositoryCommits(ctx, client, org, node.Name, author, window, &node.DefaultBranchRef.Target.Fragment.History, maxPages)
+			if err != nil {
+				return PageInfo{}, err
+			}
+			repositories.Nodes = append(repositories.Nodes, node)
+		}
+		repositories.PageInfo = page.PageInfo
+		return page.PageInfo, nil
+	}
+	pageInfo, err := fetchPage(nil)
+	if err != nil {
+		return nil, err
+	}
+	// all repositories are always scanned, maxPages only limits the commit
+	// history fetched per repository
+	err = walkPages(pageInfo, 0, fetchPage)
+	if err != nil {
+		return nil, err
+	}
+	return &commitsByUserInOrg, nil
+}
+
+func fetchRemainingRepositoryCommits(ctx context.Context, client Querier, org, repo string, author githubv4.CommitAuthor, window ReportWindow, history *RepositoryNodeRefTargetHistory, maxPages int) error {
+	return walkPages(history.PageInfo, maxPages, func(cursor *githubv4.String) (PageInfo, error) {
+		var query struct {
+			Repository struct {
+				DefaultBranchRef struct {
+					Target struct {
+						Commit struct {
+							History RepositoryNodeRefTargetHistory `graphql:"history(first: 100, after: $cursor, author: $author, since: $since, until: $until)"`
+						} `graphql:"... on Commit"`
+					}
+				}
+			} `graphql:"commitsByUserInRepository: repository(owner: $org, name: $repo)"`
+		}
+		variables := map[string]interface{}{
+			"org":    githubv4.String(org),
+			"repo":   githubv4.String(repo),
+			"author": author,
+			"since":  githubv4.GitTimestamp{Time: window.Since},
+			"until":  githubv4.GitTimestamp{Time: window.Until},
+			"cursor": cursor,
+		}
+		err := client.Query(ctx, &query, variables)
+		if err != nil {
+			return PageInfo{}, fmt.Errorf("failed to use github query %+v with variables %v: %w", query, variables, err)
+		}
+		page := query.Repository.DefaultBranchRef.Target.Commit.History
+		history.PageInfo = page.PageInfo
+		history.Nodes = append(history.Nodes, page.Nodes...)
+		return page.PageInfo, nil
+	})
+}
+
+// activityFileName returns the file name of the activity log of the user
+// for the scope and window, i.e.
+// `user-activity-testuser-kubevirt_community-2024-01-01_2024-06-30.yaml`,
+// where both dates are inclusive.
+func activityFileName(userName, scopeName string, window ReportWindow, format Format) string {
+	return fmt.Sprintf("user-activity-%s-%s-%s_%s.%s", userName, scopeName,
+		window.Since.Format(time.DateOnly), window.lastDay().Format(time.DateOnly), format.Extension())
+}
+
+// writeActivityToFile writes the report into the file in dir, replacing an
+// existing file of the same name, and returns the path of the file.
+func writeActivityToFile(report ContributionReport, dir, fileName string, format Format) (string, error) {
+	formatter, err := NewFormatter(format)
+	if err != nil {
+		return "", err
+	}
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", fmt.Errorf("failed to create output dir: %v", err)
+	}
+	path := filepath.Join(dir, fileName)
+	// write to a temporary file first, so that an interrupted write does not
+	// leave a broken file behind
+	tempPath := path + ".tmp"
+	file, err := os.Create(tempPath)
+	if err != nil {
+		return "", err
+	}
+	err = formatter.Format(file, report)
+	closeErr := file.Close()
+	if err != nil {
+		return "", err
+	}
+	if closeErr != nil {
+		return "", closeErr
+	}
+	err = os.Rename(tempPath, path)
+	if err != nil {
+		return "", err
+	}
+	log.Debugf(`user activity log: %q`, path)
+	return path, nil
+}
@@ -0,0 +1,298 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestGenerator(t *testing.T, scenario string, opts ContributionReportGeneratorOptions) *ContributionReportGenerator {
+	t.Helper()
+	opts.Querier = newFixtureQuerier(t, scenario)
+	opts.RetryAttempts = 2
+	opts.RetryDelay = time.Millisecond
+	generator, err := NewContributionReportGenerator(opts)
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	return generator
+}
+
+func TestGenerateReportForRepository(t *testing.T) {
+	generator := newTestGenerator(t, "repository", ContributionReportGeneratorOptions{
+		Org:    "kubevirt",
+		Repo:   "community",
+		Months: 6,
+	})
+
+	report, err := generator.GenerateReport(context.Background(), "testuser")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repoReport, ok := report.(*UserContributionReportForRepository)
+	if !ok {
+		t.Fatalf("expected repository report, got %T", report)
+	}
+
+	if repoReport.UserID != "U_testuser" {
+		t.Errorf("user id: got %q, want %q", repoReport.UserID, "U_testuser")
+	}
+	if !repoReport.HasContributions() {
+		t.Errorf("expected contributions")
+	}
+	counts := []struct {
+		name      string
+		got, want int
+	}{
+		{"issues created", repoReport.IssuesCreated.IssueCount, 2},
+		{"issues created nodes", len(repoReport.IssuesCreated.Nodes), 2},
+		{"issues commented", repoReport.IssuesCommented.IssueCount, 1},
+		{"issue comment nodes", len(repoReport.IssuesCommented.Nodes[0].Issue.Comments.Nodes), 2},
+		{"pull requests created", repoReport.PullRequestsCreated.IssueCount, 1},
+		{"pull requests reviewed", repoReport.PullRequestsReviewed.IssueCount, 1},
+		{"pull requests commented", repoReport.PullRequestsCommented.IssueCount, 1},
+		{"commits", repoReport.CommitsByUser.DefaultBranchRef.Target.Fragment.History.TotalCount, 2},
+	}
+	for _, count := range counts {
+		if count.got != count.want {
+			t.Errorf("%s: got %d, want %d", count.name, count.got, count.want)
+		}
+	}
+	if !strings.Contains(repoReport.Summary(), "repository:    kubevirt/community") {
+		t.Errorf("summary does not contain repository: %s", repoReport.Summary())
+	}
+}
+
+func TestGenerateReportForOrganization(t *testing.T) {
+	testCases := []struct {
+		name                     string
+		scenario                 string
+		userName                 string
+		includeArchived          bool
+		maxPages                 int
+		expectedHasContributions bool
+		expectedCommits          int
+		expectedCommitNodes      int
+		expectedRepositories     []string
+	}{
+		{
+			name:                     "active user",
+			scenario:                 "organization",
+			userName:                 "testuser",
+			expectedHasContributions: true,
+			expectedCommits:          4,
+			expectedCommitNodes:      4,
+			expectedRepositories:     []string{"kubevirt", "user-guide"},
+		},
+		{
+			name:                     "max pages don't limit the repositories",
+			scenario:                 "organization",
+			userName:                 "testuser",
+			maxPages:                 1,
+			expectedHasContributions: true,
+			expectedCommits:          4,
+			expectedCommitNodes:      2,
+			expectedRepositories:     []string{"kubevirt", "user-guide"},
+		},
+		{
+			name:                     "including archived repositories",
+			scenario:                 "organization",
+			userName:                 "testuser",
+			includeArchived:          true,
+			expectedHasContributions: true,
+			expectedCommits:          1,
+			expectedCommitNodes:      1,
+			expectedRepositories:     []string{"archived"},
+		},
+		{
+			name:                     "inactive user",
+			scenario:                 "inactive",
+			userName:                 "inactiveuser",
+			expectedHasContributions: false,
+			expectedCommits:          0,
+			expectedCommitNodes:      0,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			generator := newTestGenerator(t, testCase.scenario, ContributionReportGeneratorOptions{
+				Org:             "kubevirt",
+				Months:          6,
+				IncludeArchived: testCase.includeArchived,
+				MaxPages:        testCase.maxPages,
+			})
+
+			report, err := generator.GenerateReport(context.Background(), testCase.userName)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			orgReport, ok := report.(*UserContributionReportForOrganization)
+			if !ok {
+				t.Fatalf("expected organization report, got %T", report)
+			}
+			if orgReport.HasContributions() != testCase.expectedHasContributions {
+				t.Errorf("has contributions: got %t, want %t", orgReport.HasContributions(), testCase.expectedHasContributions)
+			}
+			if orgReport.totalCommitCount() != testCase.expectedCommits {
+				t.Errorf("commits: got %d, want %d", orgReport.totalCommitCount(), testCase.expectedCommits)
+			}
+			var repositories []string
+			commitNodes := 0
+			for _, node := range orgReport.CommitsByUserInOrg.Repositories.Nodes {
+				repositories = append(repositories, node.Name)
+				commitNodes += len(node.DefaultBranchRef.Target.Fragment.History.Nodes)
+			}
+			if !reflect.DeepEqual(repositories, testCase.expectedRepositories) {
+				t.Errorf("repositories: got %v, want %v", repositories, testCase.expectedRepositories)
+			}
+			if commitNodes != testCase.expectedCommitNodes {
+				t.Errorf("commit nodes: got %d, want %d", commitNodes, testCase.expectedCommitNodes)
+			}
+		})
+	}
+}
+
+func TestGenerateReportQueryFailure(t *testing.T) {
+	testCases := []struct {
+		name     string
+		scenario string
+	}{
+		{
+			name:     "server error",
+			scenario: "server-error",
+		},
+		{
+			name:     "query error",
+			scenario: "query-error",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			generator := newTestGenerator(t, testCase.scenario, ContributionReportGeneratorOptions{
+				Org:    "kubevirt",
+				Months: 6,
+			})
+
+			report, err := generator.GenerateReport(context.Background(), "testuser")
+			if err == nil {
+				t.Errorf("expected error, got report %v", report)
+			}
+		})
+	}
+}
+
+func TestGenerateReportCanceled(t *testing.T) {
+	generator := newTestGenerator(t, "repository", ContributionReportGeneratorOptions{
+		Org:    "kubevirt",
+		Repo:   "kubevirt",
+		Months: 6,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := generator.GenerateReport(ctx, "testuser")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestGenerateReportForScopes(t *testing.T) {
+	generator := newTestGenerator(t, "sig", ContributionReportGeneratorOptions{
+		Scopes: []Scope{
+			{Org: "kubevirt", Repo: "community"},
+			{Org: "kubevirt", Repo: "kubevirt"},
+		},
+		ScopesName: "sig-test",
+		Months:     6,
+	})
+
+	report, err := generator.GenerateReport(context.Background(), "testuser")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	scopesReport, ok := report.(*UserContributionReportForScopes)
+	if !ok {
+		t.Fatalf("expected scopes report, got %T", report)
+	}
+	if len(scopesReport.RepositoryReports) != 2 {
+		t.Fatalf("repository reports: got %d, want 2", len(scopesReport.RepositoryReports))
+	}
+	if !scopesReport.HasContributions() {
+		t.Errorf("expected contributions")
+	}
+	activityCounts := scopesReport.ActivityCounts()
+	if activityCounts[IssuesCreatedActivity] != 2 {
+		t.Errorf("issues created: got %d, want 2", activityCounts[IssuesCreatedActivity])
+	}
+	if activityCounts[CommitsActivity] != 3 {
+		t.Errorf("commits: got %d, want 3", activityCounts[CommitsActivity])
+	}
+	for _, expected := range []string{"scopes:        sig-test", "kubevirt/community:", "kubevirt/kubevirt:"} {
+		if !strings.Contains(scopesReport.Summary(), expected) {
+			t.Errorf("summary does not contain %q: %s", expected, scopesReport.Summary())
+		}
+	}
+}
+
+func TestGenerateReportForMultipleOrganizations(t *testing.T) {
+	generator := newTestGenerator(t, "multiple-orgs", ContributionReportGeneratorOptions{
+		Scopes: []Scope{
+			{Org: "kubevirt", Repo: "community"},
+			{Org: "nmstate"},
+		},
+		ScopesName: "kubevirt_community+nmstate",
+		Months:     6,
+	})
+
+	report, err := generator.GenerateReport(context.Background(), "testuser")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	scopesReport, ok := report.(*UserContributionReportForScopes)
+	if !ok {
+		t.Fatalf("expected scopes report, got %T", report)
+	}
+	if len(scopesReport.RepositoryReports) != 1 || len(scopesReport.OrganizationReports) != 1 {
+		t.Fatalf("expected one repository and one organization report, got %d and %d", len(scopesReport.RepositoryReports), len(scopesReport.OrganizationReports))
+	}
+
+	orgs, orgActivityCounts := scopesReport.orgActivityCounts()
+	if !reflect.DeepEqual(orgs, []string{"kubevirt", "nmstate"}) {
+		t.Errorf("orgs: got %v", orgs)
+	}
+	if orgActivityCounts["nmstate"][PullRequestsCreatedActivity] != 1 || orgActivityCounts["nmstate"][CommitsActivity] != 1 {
+		t.Errorf("nmstate activity counts: got %v", orgActivityCounts["nmstate"])
+	}
+	if orgActivityCounts["kubevirt"][CommitsActivity] != 2 {
+		t.Errorf("kubevirt activity counts: got %v", orgActivityCounts["kubevirt"])
+	}
+	activityCounts := scopesReport.ActivityCounts()
+	if activityCounts[PullRequestsCreatedActivity] != 2 || activityCounts[CommitsActivity] != 3 {
+		t.Errorf("activity counts: got %v", activityCounts)
+	}
+	if !strings.Contains(scopesReport.Summary(), "per org:\n        kubevirt:") {
+		t.Errorf("summary does not contain per org breakdown: %s", scopesReport.Summary())
+	}
+}
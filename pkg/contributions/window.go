@@ -0,0 +1,158 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"context"
+	"fmt"
+	"github.com/shurcooL/githubv4"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// ReportWindow is the time span that a contribution report covers. Since
+// is inclusive, Until is exclusive.
+type ReportWindow struct {
+	Since time.Time `yaml:"since"`
+	Until time.Time `yaml:"until"`
+}
+
+func (w ReportWindow) String() string {
+	return fmt.Sprintf("%s..%s", w.Since.Format(time.DateOnly), w.lastDay().Format(time.DateOnly))
+}
+
+// searchRange returns the window as a date range for the GitHub search
+// qualifiers `created` and `updated`, where both dates are inclusive.
+func (w ReportWindow) searchRange() string {
+	return w.String()
+}
+
+// contains returns whether t lies within the window.
+func (w ReportWindow) contains(t time.Time) bool {
+	return !t.Before(w.Since) && t.Before(w.Until)
+}
+
+// searchContains returns whether t lies on one of the days of the
+// searchRange, which is how the search matches the `created` and `updated`
+// qualifiers.
+func (w ReportWindow) searchContains(t time.Time) bool {
+	day := t.In(w.Since.Location()).Format(time.DateOnly)
+	return day >= w.Since.Format(time.DateOnly) && day <= w.lastDay().Format(time.DateOnly)
+}
+
+func (w ReportWindow) lastDay() time.Time {
+	return w.Until.Add(-time.Nanosecond)
+}
+
+// NewReportWindow creates the window from since until the end of the day
+// of until, where both dates are expected in the format `2006-01-02`.
+func NewReportWindow(since, until string) (ReportWindow, error) {
+	sinceDate, err := time.Parse(time.DateOnly, since)
+	if err != nil {
+		return ReportWindow{}, fmt.Errorf("invalid since date %q: %v", since, err)
+	}
+	untilDate, err := time.Parse(time.DateOnly, until)
+	if err != nil {
+		return ReportWindow{}, fmt.Errorf("invalid until date %q: %v", until, err)
+	}
+	window := ReportWindow{Since: sinceDate, Until: untilDate.AddDate(0, 0, 1)}
+	if !window.Since.Before(window.Until) {
+		return ReportWindow{}, fmt.Errorf("since date %q is after until date %q", since, until)
+	}
+	return window, nil
+}
+
+var minorReleasePattern = regexp.MustCompile(`^v?(\d+)\.(\d+)(\.0)?$`)
+
+// ReleaseCycleWindow determines the window of the release cycle that ended
+// with the minor release (i.e. `v1.4` or `v1.4.0`) of the given repository.
+// The cycle starts with the publication of the previous minor release and
+// ends with the publication of the release itself.
+func ReleaseCycleWindow(ctx context.Context, querier Querier, org, repo, release string) (ReportWindow, error) {
+	matches := minorReleasePattern.FindStringSubmatch(release)
+	if matches == nil {
+		return ReportWindow{}, fmt.Errorf("release %q is not a minor release, expected format v1.4 or v1.4.0", release)
+	}
+	major, _ := strconv.Atoi(matches[1])
+	minor, _ := strconv.Atoi(matches[2])
+	releaseTag := fmt.Sprintf("v%d.%d.0", major, minor)
+
+	type releaseNode struct {
+		TagName      string
+		PublishedAt  time.Time
+		IsPrerelease bool
+		IsDraft      bool
+	}
+	var releases []releaseNode
+	fetchPage := func(cursor *githubv4.String) (PageInfo, error) {
+		var query struct {
+			Repository struct {
+				Releases struct {
+					PageInfo PageInfo
+					Nodes    []releaseNode
+				} `graphql:"releases(first: 100, after: $cursor, orderBy: {field: CREATED_AT, direction: DESC})"`
+			} `graphql:"repository(owner: $org, name: $repo)"`
+		}
+		variables := map[string]interface{}{
+			"org":    githubv4.String(org),
+			"repo":   githubv4.String(repo),
+			"cursor": cursor,
+		}
+		err := querier.Query(ctx, &query, variables)
+		if err != nil {
+			return PageInfo{}, fmt.Errorf("failed to use github query %+v with variables %v: %w", query, variables, err)
+		}
+		releases = append(releases, query.Repository.Releases.Nodes...)
+		return query.Repository.Releases.PageInfo, nil
+	}
+	pageInfo, err := fetchPage(nil)
+	if err != nil {
+		return ReportWindow{}, err
+	}
+	err = walkPages(pageInfo, 0, fetchPage)
+	if err != nil {
+		return ReportWindow{}, err
+	}
+
+	var window ReportWindow
+	for _, r := range releases {
+		if r.TagName == releaseTag {
+			window.Until = r.PublishedAt
+		}
+	}
+	if window.Until.IsZero() {
+		return ReportWindow{}, fmt.Errorf("release %s not found in %s/%s", releaseTag, org, repo)
+	}
+	// the cycle starts with the latest minor release published before
+	for _, r := range releases {
+		if r.IsPrerelease || r.IsDraft || !r.PublishedAt.Before(window.Until) || r.PublishedAt.Before(window.Since) {
+			continue
+		}
+		if matches := minorReleasePattern.FindStringSubmatch(r.TagName); matches == nil || matches[3] == "" {
+			continue
+		}
+		window.Since = r.PublishedAt
+	}
+	if window.Since.IsZero() {
+		return ReportWindow{}, fmt.Errorf("no minor release found before %s in %s/%s", releaseTag, org, repo)
+	}
+	return window, nil
+}
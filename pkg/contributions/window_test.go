@@ -0,0 +1,119 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package contributions
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNewReportWindow(t *testing.T) {
+	testCases := []struct {
+		name                string
+		since               string
+		until               string
+		expectedSearchRange string
+		expectedErr         bool
+	}{
+		{
+			name:                "valid window",
+			since:               "2024-01-01",
+			until:               "2024-06-30",
+			expectedSearchRange: "2024-01-01..2024-06-30",
+		},
+		{
+			name:                "single day",
+			since:               "2024-01-01",
+			until:               "2024-01-01",
+			expectedSearchRange: "2024-01-01..2024-01-01",
+		},
+		{
+			name:        "since after until",
+			since:       "2024-06-30",
+			until:       "2024-01-01",
+			expectedErr: true,
+		},
+		{
+			name:        "invalid date",
+			since:       "01/01/2024",
+			until:       "2024-01-01",
+			expectedErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			window, err := NewReportWindow(testCase.since, testCase.until)
+			if (err != nil) != testCase.expectedErr {
+				t.Fatalf("error: got %v, expected error %t", err, testCase.expectedErr)
+			}
+			if err == nil && window.searchRange() != testCase.expectedSearchRange {
+				t.Errorf("search range: got %q, want %q", window.searchRange(), testCase.expectedSearchRange)
+			}
+		})
+	}
+}
+
+func TestReleaseCycleWindow(t *testing.T) {
+	testCases := []struct {
+		name           string
+		release        string
+		expectedWindow ReportWindow
+		expectedErr    bool
+	}{
+		{
+			name:    "latest release",
+			release: "v1.4",
+			expectedWindow: ReportWindow{
+				Since: time.Date(2024, 7, 17, 10, 0, 0, 0, time.UTC),
+				Until: time.Date(2024, 11, 13, 10, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "previous release",
+			release: "1.3.0",
+			expectedWindow: ReportWindow{
+				Since: time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC),
+				Until: time.Date(2024, 7, 17, 10, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:        "no previous release",
+			release:     "v1.2",
+			expectedErr: true,
+		},
+		{
+			name:        "patch release",
+			release:     "v1.4.1",
+			expectedErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			window, err := ReleaseCycleWindow(context.Background(), newFixtureQuerier(t, "releases"), "kubevirt", "kubevirt", testCase.release)
+			if (err != nil) != testCase.expectedErr {
+				t.Fatalf("error: got %v, expected error %t", err, testCase.expectedErr)
+			}
+			if window != testCase.expectedWindow {
+				t.Errorf("window: got %v, want %v", window, testCase.expectedWindow)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package sigs
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+var repoNameMatcher = regexp.MustCompile(`^https://raw.githubusercontent.com/([^/]+/[^/]+)/.*$`)
+
+// Group returns the group (sig, working group, user group or committee)
+// whose name or dir matches the given name, or nil if there is none.
+func (s *Sigs) Group(name string) *Group {
+	for _, groups := range [][]*Group{s.Sigs, s.Workinggroups, s.Usergroups, s.Committees} {
+		for _, group := range groups {
+			if group.Name == name || group.Dir == name {
+				return group
+			}
+		}
+	}
+	return nil
+}
+
+// Repositories returns the sorted set of repositories (as "org/repo")
+// referenced by the OWNERS files of the group's subprojects.
+func (g *Group) Repositories() ([]string, error) {
+	repoMap := make(map[string]struct{})
+	for _, subProject := range g.SubProjects {
+		for _, ownerRef := range subProject.Owners {
+			stringSubmatch := repoNameMatcher.FindStringSubmatch(ownerRef)
+			if stringSubmatch == nil {
+				return nil, fmt.Errorf("owners reference %q of subproject %q doesn't match %s", ownerRef, subProject.Name, repoNameMatcher)
+			}
+			repoMap[stringSubmatch[1]] = struct{}{}
+		}
+	}
+	var repos []string
+	for repo := range repoMap {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	return repos, nil
+}
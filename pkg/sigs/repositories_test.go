@@ -0,0 +1,144 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright the KubeVirt Authors.
+ *
+ */
+
+package sigs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSigsGroup(t *testing.T) {
+	sigsYAML := &Sigs{
+		Sigs: []*Group{
+			{Name: "sig-compute", Dir: "sig-compute"},
+			{Name: "sig-ci", Dir: "sig-testing"},
+		},
+		Workinggroups: []*Group{
+			{Name: "wg-arch", Dir: "wg-arch"},
+		},
+		Committees: []*Group{
+			{Name: "Code of Conduct", Dir: "code-of-conduct"},
+		},
+	}
+	testCases := []struct {
+		name         string
+		groupName    string
+		expectedName string
+	}{
+		{
+			name:         "sig by name",
+			groupName:    "sig-ci",
+			expectedName: "sig-ci",
+		},
+		{
+			name:         "sig by dir",
+			groupName:    "sig-testing",
+			expectedName: "sig-ci",
+		},
+		{
+			name:         "working group",
+			groupName:    "wg-arch",
+			expectedName: "wg-arch",
+		},
+		{
+			name:         "committee by dir",
+			groupName:    "code-of-conduct",
+			expectedName: "Code of Conduct",
+		},
+		{
+			name:      "no match",
+			groupName: "sig-storage",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			group := sigsYAML.Group(testCase.groupName)
+			if testCase.expectedName == "" {
+				if group != nil {
+					t.Errorf("expected no group, got %q", group.Name)
+				}
+				return
+			}
+			if group == nil {
+				t.Fatalf("expected group %q, got none", testCase.expectedName)
+			}
+			if group.Name != testCase.expectedName {
+				t.Errorf("got %q, want %q", group.Name, testCase.expectedName)
+			}
+		})
+	}
+}
+
+func TestGroupRepositories(t *testing.T) {
+	testCases := []struct {
+		name          string
+		subProjects   []*SubProject
+		expected      []string
+		expectedError bool
+	}{
+		{
+			name: "deduplicated and sorted",
+			subProjects: []*SubProject{
+				{
+					Name: "kubevirt",
+					Owners: []string{
+						"https://raw.githubusercontent.com/kubevirt/kubevirt/main/pkg/virt-handler/OWNERS",
+						"https://raw.githubusercontent.com/kubevirt/kubevirt/main/pkg/virt-launcher/OWNERS",
+					},
+				},
+				{
+					Name: "community",
+					Owners: []string{
+						"https://raw.githubusercontent.com/kubevirt/community/main/OWNERS",
+						"https://raw.githubusercontent.com/kubevirt/kubevirt/main/OWNERS",
+					},
+				},
+			},
+			expected: []string{"kubevirt/community", "kubevirt/kubevirt"},
+		},
+		{
+			name:        "no subprojects",
+			subProjects: nil,
+			expected:    nil,
+		},
+		{
+			name: "owners reference not matching",
+			subProjects: []*SubProject{
+				{
+					Name:   "kubevirt",
+					Owners: []string{"https://github.com/kubevirt/kubevirt/blob/main/OWNERS"},
+				},
+			},
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			group := &Group{Name: "sig-compute", SubProjects: testCase.subProjects}
+			repos, err := group.Repositories()
+			if testCase.expectedError != (err != nil) {
+				t.Fatalf("expected error %t, got %v", testCase.expectedError, err)
+			}
+			if !reflect.DeepEqual(repos, testCase.expected) {
+				t.Errorf("got %v, want %v", repos, testCase.expected)
+			}
+		})
+	}
+}